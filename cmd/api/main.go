@@ -1,20 +1,43 @@
 package main
 
 import (
+	"context"
 	"log"
+	// Base de fusos embutida, para imagens sem tzdata instalado
+	_ "time/tzdata"
+	"youmeet/internal/adapters/handlers/admin_handler"
 	"youmeet/internal/adapters/handlers/appointment_handler"
 	"youmeet/internal/adapters/handlers/auth_handler"
+	"youmeet/internal/adapters/handlers/availability_handler"
+	"youmeet/internal/adapters/handlers/company_handler"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/adapters/handlers/policy_handler"
+	"youmeet/internal/adapters/handlers/service_handler"
+	"youmeet/internal/adapters/handlers/waitlist_handler"
 	"youmeet/internal/adapters/repositories"
 	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/idempotency"
 	"youmeet/internal/core/domain/service"
 	"youmeet/internal/core/domain/user"
 	"youmeet/internal/core/services"
+	"youmeet/internal/infra/config"
 	"youmeet/internal/infra/database"
+	"youmeet/internal/infra/email"
+	"youmeet/internal/infra/jobs"
+	"youmeet/internal/infra/oidc"
+	"youmeet/internal/infra/token"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
 	// Database connection (PostgreSQL or SQLite based on env)
 	db, err := database.NewDBClient()
 	if err != nil {
@@ -26,8 +49,26 @@ func main() {
 		&user.User{},
 		&user.Company{},
 		&user.Professional{},
+		&auth.RefreshToken{},
+		&auth.ActionToken{},
+		&auth.LoginThrottle{},
+		&auth.RecoveryCode{},
+		&auth.APIKey{},
+		&auth.UserIdentity{},
+		&auth.OAuthState{},
+		&audit.AuditLog{},
+		&idempotency.Record{},
 		&appointment.Appointment{},
+		&appointment.StatusChange{},
+		&appointment.Reschedule{},
+		&appointment.Series{},
+		&appointment.Hold{},
+		&appointment.WaitlistEntry{},
 		&appointment.Availability{},
+		&appointment.AvailabilityBreak{},
+		&appointment.AvailabilityException{},
+		&appointment.Holiday{},
+		&appointment.Policy{},
 		&service.Service{},
 	)
 	if err != nil {
@@ -39,28 +80,231 @@ func main() {
 	companyRepo := repositories.NewCompanyRepository(db)
 	profRepo := repositories.NewProfessionalRepository(db)
 	appointmentRepo := repositories.NewAppointmentRepository(db)
+	holdRepo := repositories.NewHoldRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
 	serviceRepo := repositories.NewServiceRepository(db)
+	availabilityRepo := repositories.NewAvailabilityRepository(db)
+	exceptionRepo := repositories.NewExceptionRepository(db)
+	policyRepo := repositories.NewPolicyRepository(db)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
+	actionTokenRepo := repositories.NewActionTokenRepository(db)
+	loginThrottleRepo := repositories.NewLoginThrottleRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	recoveryCodeRepo := repositories.NewRecoveryCodeRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	userIdentityRepo := repositories.NewUserIdentityRepository(db)
+	oauthStateRepo := repositories.NewOAuthStateRepository(db)
+	idempotencyRepo := repositories.NewIdempotencyRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+
+	// Tokens de acesso (HS256 ou RS256)
+	tokenManager, err := token.NewJWTManager(cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to configure JWT:", err)
+	}
+
+	// Envio de emails (log ou arquivo em desenvolvimento)
+	emailSender, err := email.NewEmailSender(cfg.Email)
+	if err != nil {
+		log.Fatal("Failed to configure email sender:", err)
+	}
+
+	// Provedores de login social (OIDC e, em desenvolvimento, o provedor fake)
+	identityProviders, fakeProvider, err := oidc.NewProviders(context.Background(), cfg.OIDC)
+	if err != nil {
+		log.Fatal("Failed to configure identity providers:", err)
+	}
 
 	// Serviços
-	authService := services.NewAuthService(userRepo, companyRepo, profRepo)
-	bookingService := services.NewBookingService(appointmentRepo, serviceRepo)
+	loginGuard := services.NewLoginGuard(loginThrottleRepo, auditRepo, services.LoginGuardSettings{
+		MaxFailuresPerEmail: cfg.Login.MaxFailuresPerEmail,
+		MaxFailuresPerIP:    cfg.Login.MaxFailuresPerIP,
+		FreeAttempts:        cfg.Login.FreeAttempts,
+		BaseDelay:           cfg.Login.BaseDelay,
+		MaxDelay:            cfg.Login.MaxDelay,
+		LockoutDuration:     cfg.Login.LockoutDuration,
+	})
+	mfaService := services.NewMFAService(userRepo, companyRepo, recoveryCodeRepo, cfg.Auth.MFAIssuer)
+	authService := services.NewAuthService(userRepo, companyRepo, profRepo, refreshTokenRepo, tokenManager, loginGuard, mfaService, services.AuthSettings{
+		AccessTokenTTL:  cfg.JWT.AccessTTL,
+		RefreshTokenTTL: cfg.JWT.RefreshTTL,
+		MFAChallengeTTL: cfg.Auth.MFAChallengeTTL,
+	})
+	accountService := services.NewAccountService(userRepo, actionTokenRepo, refreshTokenRepo, emailSender, services.AccountSettings{
+		EmailVerificationTTL: cfg.Auth.EmailVerificationTTL,
+		PasswordResetTTL:     cfg.Auth.PasswordResetTTL,
+		LinkBaseURL:          cfg.Email.LinkBaseURL,
+	})
+	oidcService := services.NewOIDCService(identityProviders, oauthStateRepo, userIdentityRepo, userRepo, authService, cfg.OIDC.StateTTL)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, companyRepo, userRepo)
+	policyService := services.NewPolicyService(policyRepo, profRepo)
+	bookingService := services.NewBookingService(appointmentRepo, holdRepo, waitlistRepo, availabilityRepo, exceptionRepo, profRepo, serviceRepo, policyService, txManager, services.BookingSettings{
+		Buffer:         cfg.Booking.BufferTime,
+		HoldTTL:        cfg.Booking.HoldTTL,
+		MaxAdvance:     cfg.Booking.MaxSearchRange,
+		MaxActiveHolds: cfg.Booking.MaxHoldsPerClient,
+	})
+	waitlistService := services.NewWaitlistService(waitlistRepo, holdRepo, appointmentRepo, serviceRepo, userRepo, bookingService, emailSender, txManager, services.WaitlistSettings{
+		OfferTTL:    cfg.Booking.WaitlistOfferTTL,
+		LinkBaseURL: cfg.Email.LinkBaseURL,
+		Buffer:      cfg.Booking.BufferTime,
+	})
+	lifecycleService := services.NewLifecycleService(appointmentRepo, policyService, waitlistService, txManager)
+	sessionService := services.NewSessionService(appointmentRepo, holdRepo, serviceRepo, userRepo)
+	catalogService := services.NewCatalogService(serviceRepo)
+	availabilityService := services.NewAvailabilityService(availabilityRepo, exceptionRepo, appointmentRepo, companyRepo, profRepo, txManager)
+	slotService := services.NewSlotService(serviceRepo, appointmentRepo, holdRepo, availabilityRepo, exceptionRepo, profRepo, services.SlotSettings{
+		Granularity: cfg.Booking.SlotGranularity,
+		Buffer:      cfg.Booking.BufferTime,
+		MaxRange:    cfg.Booking.MaxSearchRange,
+	})
+	authzService := services.NewAuthorizationService(appointmentRepo, holdRepo, waitlistRepo, companyRepo, profRepo, userRepo)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, services.IdempotencySettings{
+		KeyTTL:  cfg.Idempotency.KeyTTL,
+		LockTTL: cfg.Idempotency.LockTTL,
+	})
+	adminService := services.NewAdminService(userRepo, auditRepo, tokenManager, cfg.Admin.ImpersonationTTL)
+
+	// Conta inicial de administrador (apenas com ADMIN_EMAIL definido)
+	if cfg.Admin.Email != "" {
+		if err := adminService.EnsureAdmin(context.Background(), cfg.Admin.Name, cfg.Admin.Email, cfg.Admin.Password); err != nil {
+			log.Fatal("Failed to create admin account:", err)
+		}
+	}
 
 	// Handlers
-	authHandler := auth_handler.NewHandler(authService)
-	appointmentHandler := appointment_handler.NewHandler(bookingService)
+	authHandler := auth_handler.NewHandler(authService, accountService, mfaService, oidcService)
+	appointmentHandler := appointment_handler.NewHandler(bookingService, lifecycleService, sessionService, authzService)
+	companyHandler := company_handler.NewHandler(mfaService, apiKeyService)
+	serviceHandler := service_handler.NewHandler(catalogService, slotService)
+	adminHandler := admin_handler.NewHandler(adminService)
+	availabilityHandler := availability_handler.NewHandler(availabilityService)
+	policyHandler := policy_handler.NewHandler(policyService)
+	waitlistHandler := waitlist_handler.NewHandler(waitlistService)
+
+	// Middlewares
+	authMiddleware := middleware.NewAuth(authService, apiKeyService, adminService)
+	idempotent := middleware.Idempotency(idempotencyService)
 
 	r := gin.Default()
+	r.Use(middleware.TimeZone())
 
 	// Rotas de autenticação
-	auth := r.Group("/auth")
+	authRoutes := r.Group("/auth")
+	{
+		authRoutes.POST("/register", authHandler.Register)
+		authRoutes.POST("/login", authHandler.Login)
+		authRoutes.POST("/refresh", authHandler.Refresh)
+		authRoutes.POST("/logout", authHandler.Logout)
+		authRoutes.POST("/logout-all", authMiddleware.Required(), middleware.RejectImpersonation(), authHandler.LogoutAll)
+		authRoutes.POST("/email-verification", authMiddleware.Required(), authHandler.RequestEmailVerification)
+		authRoutes.POST("/email-verification/confirm", authHandler.ConfirmEmail)
+		authRoutes.POST("/password-reset", authHandler.RequestPasswordReset)
+		authRoutes.POST("/password-reset/confirm", authHandler.ConfirmPasswordReset)
+		authRoutes.POST("/mfa/verify", authHandler.VerifyMFA)
+		authRoutes.GET("/oidc/:provider/login", authHandler.OIDCLogin)
+		authRoutes.GET("/oidc/:provider/callback", authHandler.OIDCCallback)
+	}
+
+	// Tela de autorização do provedor fake (apenas com OIDC_FAKE_ENABLED=true)
+	if fakeProvider != nil {
+		r.GET("/oidc/fake/authorize", gin.WrapF(fakeProvider.Authorize))
+	}
+
+	// Cadastro do segundo fator (empresas e profissionais)
+	mfaRoutes := r.Group("/auth/mfa", authMiddleware.RequiredAllowingMFAEnrollment(), middleware.RejectImpersonation(), middleware.RequireRole("company", "professional"))
+	{
+		mfaRoutes.POST("/enroll", authHandler.EnrollMFA)
+		mfaRoutes.POST("/confirm", authHandler.ConfirmMFA)
+	}
+
+	// Rotas de agendamentos (autenticadas; POST também aceita chaves de API)
+	appointments := r.Group("/appointments")
+	{
+		appointments.POST("", authMiddleware.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), middleware.Authorize("", services.BookAppointment), middleware.RequireVerifiedEmail(cfg.Auth.RequireEmailVerification), idempotent, appointmentHandler.BookAppointment)
+		appointments.GET("", authMiddleware.Required(), appointmentHandler.GetAppointments)
+		appointments.POST("/series", authMiddleware.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), middleware.Authorize("", services.BookAppointment), middleware.RequireVerifiedEmail(cfg.Auth.RequireEmailVerification), idempotent, appointmentHandler.BookSeries)
+		appointments.GET("/series/:id", authMiddleware.Required(), middleware.Authorize("id", authzService.ReadSeries), appointmentHandler.GetSeries)
+		appointments.GET("/:id", authMiddleware.Required(), middleware.Authorize("id", authzService.ReadAppointment), appointmentHandler.GetAppointment)
+		appointments.GET("/:id/attendees", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.GetAttendees)
+		appointments.PUT("/:id", authMiddleware.Required(), middleware.Authorize("id", authzService.ReadAppointment), appointmentHandler.Reschedule)
+		appointments.DELETE("/:id", authMiddleware.Required(), middleware.Authorize("id", authzService.ReadAppointment), appointmentHandler.Cancel)
+		appointments.POST("/:id/confirm", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.Confirm)
+		appointments.POST("/:id/check-in", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.CheckIn)
+		appointments.POST("/:id/complete", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.Complete)
+		appointments.POST("/:id/no-show", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.MarkNoShow)
+		appointments.POST("/:id/cancel-by-provider", authMiddleware.Required(), middleware.Authorize("id", authzService.ManageAppointment), appointmentHandler.CancelByProvider)
+		appointments.POST("/:id/cancel-by-client", authMiddleware.Required(), middleware.Authorize("id", authzService.OwnAppointment), appointmentHandler.CancelByClient)
+	}
+
+	// Rotas de reservas temporárias (POST também aceita chaves de API)
+	holds := r.Group("/holds")
+	{
+		holds.POST("", authMiddleware.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), middleware.RateLimit(cfg.Booking.HoldRateLimit, cfg.Booking.HoldRateWindow), middleware.Authorize("", services.BookAppointment), middleware.RequireVerifiedEmail(cfg.Auth.RequireEmailVerification), idempotent, appointmentHandler.PlaceHold)
+		holds.GET("/:id", authMiddleware.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), middleware.Authorize("id", authzService.OwnHold), appointmentHandler.GetHold)
+		holds.DELETE("/:id", authMiddleware.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), middleware.Authorize("id", authzService.OwnHold), appointmentHandler.ReleaseHold)
+	}
+
+	// Rotas da lista de espera (clientes)
+	waitlist := r.Group("/waitlist", authMiddleware.Required())
+	{
+		waitlist.POST("", middleware.RequireRole("client"), middleware.RequireVerifiedEmail(cfg.Auth.RequireEmailVerification), waitlistHandler.Join)
+		waitlist.GET("", waitlistHandler.ListMine)
+		waitlist.GET("/:id", middleware.Authorize("id", authzService.OwnWaitlistEntry), waitlistHandler.GetEntry)
+		waitlist.DELETE("/:id", middleware.Authorize("id", authzService.OwnWaitlistEntry), waitlistHandler.Leave)
+		waitlist.POST("/:id/accept", middleware.Authorize("id", authzService.OwnWaitlistEntry), waitlistHandler.Accept)
+	}
+
+	// Rotas de serviços (usuários ou chaves de API)
+	servicesRoutes := r.Group("/services")
+	{
+		servicesRoutes.GET("", authMiddleware.RequiredOrAPIKey(auth.ScopeServicesRead), serviceHandler.ListServices)
+		servicesRoutes.GET("/:id/slots", authMiddleware.RequiredOrAPIKey(auth.ScopeServicesRead), serviceHandler.ListSlots)
+	}
+
+	// Rotas de empresas (autenticadas)
+	companies := r.Group("/companies", authMiddleware.Required())
+	{
+		companies.PUT("/:id/mfa-policy", middleware.RejectImpersonation(), middleware.Authorize("id", authzService.ManageCompany), companyHandler.SetMFAPolicy)
+		companies.POST("/:id/api-keys", middleware.RejectImpersonation(), middleware.Authorize("id", authzService.ManageCompany), companyHandler.CreateAPIKey)
+		companies.GET("/:id/api-keys", middleware.RejectImpersonation(), middleware.Authorize("id", authzService.ManageCompany), companyHandler.ListAPIKeys)
+		companies.DELETE("/:id/api-keys/:keyId", middleware.RejectImpersonation(), middleware.Authorize("id", authzService.ManageCompany), companyHandler.RevokeAPIKey)
+		companies.PUT("/:id/time-zone", middleware.Authorize("id", authzService.ManageCompany), availabilityHandler.SetCompanyTimeZone)
+		companies.GET("/:id/booking-policy", middleware.Authorize("id", authzService.ViewCompany), policyHandler.GetCompanyPolicy)
+		companies.PUT("/:id/booking-policy", middleware.Authorize("id", authzService.ManageCompany), policyHandler.SetCompanyPolicy)
+		companies.GET("/:id/holidays", middleware.Authorize("id", authzService.ViewCompany), availabilityHandler.ListHolidays)
+		companies.POST("/:id/holidays", middleware.Authorize("id", authzService.ManageCompany), availabilityHandler.CreateHoliday)
+		companies.DELETE("/:id/holidays/:holidayId", middleware.Authorize("id", authzService.ManageCompany), availabilityHandler.DeleteHoliday)
+	}
+
+	// Rotas de profissionais (autenticadas)
+	professionals := r.Group("/professionals", authMiddleware.Required())
+	{
+		professionals.GET("/:id/appointments", middleware.Authorize("id", authzService.AccessProfessional), appointmentHandler.GetProfessionalAppointments)
+		professionals.GET("/:id/waitlist", middleware.Authorize("id", authzService.AccessProfessional), waitlistHandler.ListProfessionalWaitlist)
+		professionals.GET("/:id/time-zone", availabilityHandler.GetProfessionalTimeZone)
+		professionals.PUT("/:id/time-zone", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.SetProfessionalTimeZone)
+		professionals.GET("/:id/booking-policy", middleware.Authorize("id", authzService.ViewProfessional), policyHandler.GetProfessionalPolicy)
+		professionals.PUT("/:id/booking-policy", middleware.Authorize("id", authzService.ManageProfessional), policyHandler.SetProfessionalPolicy)
+		professionals.GET("/:id/availability", availabilityHandler.ListAvailability)
+		professionals.POST("/:id/availability", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.CreateAvailability)
+		professionals.PUT("/:id/availability/:availabilityId", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.UpdateAvailability)
+		professionals.DELETE("/:id/availability/:availabilityId", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.DeleteAvailability)
+		professionals.GET("/:id/exceptions", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.ListExceptions)
+		professionals.POST("/:id/exceptions", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.CreateException)
+		professionals.DELETE("/:id/exceptions/:exceptionId", middleware.Authorize("id", authzService.ManageSchedule), availabilityHandler.DeleteException)
+	}
+
+	// Rotas da equipe de suporte (apenas administradores)
+	admin := r.Group("/admin", authMiddleware.Required(), middleware.RequireRole("admin"))
 	{
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", authHandler.Login)
+		admin.POST("/impersonations", adminHandler.Impersonate)
+		admin.GET("/audit-logs", adminHandler.ListAuditLogs)
 	}
 
-	// Rotas de agendamentos
-	r.POST("/appointments", appointmentHandler.BookAppointment)
-	r.GET("/appointments/:clientId", appointmentHandler.GetAppointments)
+	// Reservas vencidas são apagadas e as ofertas da lista de espera passam ao próximo da fila
+	go jobs.Every(context.Background(), "hold-expiry", cfg.Booking.HoldSweepInterval, waitlistService.ExpireHolds)
+	go jobs.Every(context.Background(), "idempotency-keys", cfg.Idempotency.SweepInterval, idempotencyService.PurgeExpired)
 
 	log.Println("Server starting on :8080")
 	r.Run(":8080")
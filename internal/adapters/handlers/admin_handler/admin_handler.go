@@ -0,0 +1,91 @@
+package admin_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	adminService *services.AdminService
+}
+
+func NewHandler(adminService *services.AdminService) *Handler {
+	return &Handler{
+		adminService: adminService,
+	}
+}
+
+func (h *Handler) Impersonate(c *gin.Context) {
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	session, err := h.adminService.Impersonate(c.Request.Context(), identity.User, userID, req.Reason, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, auth.ErrImpersonationNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	var query AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := audit.Filter{
+		Action: query.Action,
+		Limit:  query.Limit,
+	}
+	if query.ActorID != "" {
+		actorID, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor ID"})
+			return
+		}
+		filter.ActorID = &actorID
+	}
+	if query.SubjectID != "" {
+		subjectID, err := uuid.Parse(query.SubjectID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subject ID"})
+			return
+		}
+		filter.SubjectID = &subjectID
+	}
+
+	logs, err := h.adminService.ListAuditLogs(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
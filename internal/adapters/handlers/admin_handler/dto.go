@@ -0,0 +1,13 @@
+package admin_handler
+
+type ImpersonateRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AuditLogQuery struct {
+	Action    string `form:"action"`
+	ActorID   string `form:"actor_id" binding:"omitempty,uuid"`
+	SubjectID string `form:"subject_id" binding:"omitempty,uuid"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1"`
+}
@@ -1,20 +1,33 @@
 package appointment_handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
 	"youmeet/internal/core/services"
 )
 
 type Handler struct {
-	bookingService *services.BookingService
+	bookingService   *services.BookingService
+	lifecycleService *services.LifecycleService
+	sessionService   *services.SessionService
+	authzService     *services.AuthorizationService
 }
 
-func NewHandler(bookingService *services.BookingService) *Handler {
+func NewHandler(bookingService *services.BookingService, lifecycleService *services.LifecycleService, sessionService *services.SessionService, authzService *services.AuthorizationService) *Handler {
 	return &Handler{
-		bookingService: bookingService,
+		bookingService:   bookingService,
+		lifecycleService: lifecycleService,
+		sessionService:   sessionService,
+		authzService:     authzService,
 	}
 }
 
@@ -25,28 +38,402 @@ func (h *Handler) BookAppointment(c *gin.Context) {
 		return
 	}
 
-	appointment, err := h.bookingService.BookAppointment(c.Request.Context(), req.ServiceID, req.ClientID, req.StartTime)
+	clientID, ok := h.bookingClient(c, req.ClientID, req.ProfessionalID)
+	if !ok {
+		return
+	}
+
+	appt, err := h.bookingService.BookAppointment(c.Request.Context(), req.ServiceID, req.ProfessionalID, clientID, req.StartTime, req.HoldID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeBookingError(c, err)
+		return
+	}
+
+	appt.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, appt)
+}
+
+// PlaceHold reserva o horário para o cliente enquanto ele conclui o agendamento
+func (h *Handler) PlaceHold(c *gin.Context) {
+	var req PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clientID, ok := h.bookingClient(c, req.ClientID, req.ProfessionalID)
+	if !ok {
+		return
+	}
+
+	hold, err := h.bookingService.PlaceHold(c.Request.Context(), req.ServiceID, req.ProfessionalID, clientID, req.StartTime)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	hold.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *Handler) GetHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold ID"})
+		return
+	}
+
+	hold, err := h.bookingService.GetHold(c.Request.Context(), id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	hold.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseHold libera o horário antes do fim da reserva
+func (h *Handler) ReleaseHold(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold ID"})
+		return
+	}
+
+	if err := h.bookingService.ReleaseHold(c.Request.Context(), id); err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// BookSeries agenda as ocorrências de uma recorrência semanal ou quinzenal
+func (h *Handler) BookSeries(c *gin.Context) {
+	var req BookSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clientID, ok := h.bookingClient(c, req.ClientID, req.ProfessionalID)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingService.BookSeries(c.Request.Context(), req.ServiceID, req.ProfessionalID, clientID, req.StartTime, req.Recurrence.toDomain(), req.Mode)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	booking.Series.Localize(loc)
+	for i := range booking.Conflicts {
+		booking.Conflicts[i].Localize(loc)
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+func (h *Handler) GetSeries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid series ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, appointment)
+	series, err := h.bookingService.GetSeries(c.Request.Context(), id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	series.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, series)
 }
 
 func (h *Handler) GetAppointments(c *gin.Context) {
-	clientIDStr := c.Param("clientId")
-	clientID, err := uuid.Parse(clientIDStr)
+	identity := middleware.CurrentIdentity(c)
+
+	var appointments []*appointment.Appointment
+	var err error
+	switch {
+	case identity.Professional != nil:
+		appointments, err = h.bookingService.GetAppointmentsByProfessional(c.Request.Context(), identity.Professional.ID)
+	case identity.Company != nil:
+		appointments, err = h.bookingService.GetAppointmentsByCompany(c.Request.Context(), identity.Company.ID)
+	default:
+		appointments, err = h.bookingService.GetAppointments(c.Request.Context(), identity.User.ID)
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid client ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	appointments, err := h.bookingService.GetAppointments(c.Request.Context(), clientID)
+	localize(c, appointments)
+	c.JSON(http.StatusOK, appointments)
+}
+
+func (h *Handler) GetAppointment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return
+	}
+
+	appt, err := h.bookingService.GetAppointment(c.Request.Context(), id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	appt.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, appt)
+}
+
+func (h *Handler) GetProfessionalAppointments(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	appointments, err := h.bookingService.GetAppointmentsByProfessional(c.Request.Context(), professionalID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	localize(c, appointments)
 	c.JSON(http.StatusOK, appointments)
-}
\ No newline at end of file
+}
+
+// GetAttendees lista os clientes da sessão do agendamento e as vagas restantes
+func (h *Handler) GetAttendees(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return
+	}
+
+	session, err := h.sessionService.Attendees(c.Request.Context(), id)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	session.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *Handler) Confirm(c *gin.Context) {
+	h.transition(c, appointment.Confirm)
+}
+
+func (h *Handler) CheckIn(c *gin.Context) {
+	h.transition(c, appointment.CheckIn)
+}
+
+func (h *Handler) Complete(c *gin.Context) {
+	h.transition(c, appointment.Complete)
+}
+
+func (h *Handler) CancelByClient(c *gin.Context) {
+	h.cancel(c, false)
+}
+
+func (h *Handler) CancelByProvider(c *gin.Context) {
+	h.cancel(c, true)
+}
+
+// Cancel cancela em nome de quem chama: o cliente, sujeito à política, ou o profissional e a empresa
+func (h *Handler) Cancel(c *gin.Context) {
+	h.cancel(c, isProvider(middleware.CurrentIdentity(c)))
+}
+
+// Reschedule move o agendamento para um novo horário e/ou profissional. Com scope "following" ou "all",
+// as demais ocorrências da série são movidas junto e a resposta é a lista de agendamentos alterados.
+func (h *Handler) Reschedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return
+	}
+
+	var req RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	if req.ProfessionalID != nil {
+		if err := h.authzService.RescheduleTarget(c.Request.Context(), identity, id, *req.ProfessionalID); err != nil {
+			writeBookingError(c, err)
+			return
+		}
+	}
+
+	actor := appointment.Actor{UserID: identity.User.ID, Provider: isProvider(identity)}
+	if req.Scope == "" || req.Scope == appointment.ScopeThis {
+		appt, err := h.bookingService.Reschedule(c.Request.Context(), id, req.StartTime, req.ProfessionalID, actor)
+		if err != nil {
+			writeBookingError(c, err)
+			return
+		}
+
+		appt.Localize(middleware.RequestLocation(c))
+		c.JSON(http.StatusOK, appt)
+		return
+	}
+
+	moved, err := h.bookingService.RescheduleOccurrences(c.Request.Context(), id, req.StartTime, req.ProfessionalID, req.Scope, actor)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	localize(c, moved)
+	c.JSON(http.StatusOK, moved)
+}
+
+// cancel cancela o agendamento ou, com scope "following" ou "all", também as demais ocorrências da série,
+// respondendo com a lista de agendamentos cancelados
+func (h *Handler) cancel(c *gin.Context, provider bool) {
+	var req CancelRequest
+	id, ok := parseTransition(c, &req)
+	if !ok {
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	actor := appointment.Actor{UserID: identity.User.ID, Provider: provider}
+	if req.Scope == "" || req.Scope == appointment.ScopeThis {
+		appt, err := h.lifecycleService.Cancel(c.Request.Context(), id, actor, req.Reason)
+		if err != nil {
+			writeBookingError(c, err)
+			return
+		}
+
+		appt.Localize(middleware.RequestLocation(c))
+		c.JSON(http.StatusOK, appt)
+		return
+	}
+
+	cancelled, err := h.lifecycleService.CancelOccurrences(c.Request.Context(), id, actor, req.Reason, req.Scope)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	localize(c, cancelled)
+	c.JSON(http.StatusOK, cancelled)
+}
+
+func (h *Handler) MarkNoShow(c *gin.Context) {
+	h.transition(c, appointment.MarkNoShow)
+}
+
+// transition aplica a transição ao agendamento da rota
+func (h *Handler) transition(c *gin.Context, transition appointment.Transition) {
+	var req TransitionRequest
+	id, ok := parseTransition(c, &req)
+	if !ok {
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	appt, err := h.lifecycleService.Transition(c.Request.Context(), id, transition, identity.User.ID, req.Reason)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+
+	appt.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, appt)
+}
+
+// parseTransition lê o agendamento da rota e o corpo opcional em req
+func parseTransition(c *gin.Context, req interface{}) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid appointment ID"})
+		return uuid.Nil, false
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bookingClient retorna o cliente do agendamento: o usuário autenticado ou, para chaves de API, o client_id do corpo
+func (h *Handler) bookingClient(c *gin.Context, clientID *uuid.UUID, professionalID uuid.UUID) (uuid.UUID, bool) {
+	id, err := h.authzService.BookingClient(c.Request.Context(), middleware.CurrentIdentity(c), clientID, professionalID)
+	if err != nil {
+		writeBookingError(c, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// isProvider indica se a identidade age pelo lado do atendimento. As rotas que o usam já exigem
+// ReadAppointment, então empresas e profissionais aqui são os responsáveis pelo agendamento.
+func isProvider(identity *auth.Identity) bool {
+	return identity.Professional != nil || identity.Company != nil
+}
+
+// localize exibe os horários no fuso pedido pelo cliente
+func localize(c *gin.Context, appointments []*appointment.Appointment) {
+	loc := middleware.RequestLocation(c)
+	for _, a := range appointments {
+		a.Localize(loc)
+	}
+}
+
+func writeBookingError(c *gin.Context, err error) {
+	var seriesConflict *appointment.SeriesConflictError
+	switch {
+	case errors.As(err, &seriesConflict):
+		loc := middleware.RequestLocation(c)
+		for i := range seriesConflict.Conflicts {
+			seriesConflict.Conflicts[i].Localize(loc)
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": seriesConflict.Conflicts})
+	case errors.Is(err, appointment.ErrConflict),
+		errors.Is(err, appointment.ErrSlotHeld),
+		errors.Is(err, appointment.ErrHoldExpired),
+		errors.Is(err, appointment.ErrSessionFull),
+		errors.Is(err, appointment.ErrAlreadyAttending),
+		errors.Is(err, appointment.ErrHoldLimitReached),
+		errors.Is(err, appointment.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrPolicyViolation):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrInvalidStartTime),
+		errors.Is(err, appointment.ErrNothingToReschedule),
+		errors.Is(err, appointment.ErrHoldMismatch),
+		errors.Is(err, appointment.ErrHoldOutOfRange),
+		errors.Is(err, appointment.ErrInvalidRecurrence),
+		errors.Is(err, appointment.ErrInvalidScope),
+		errors.Is(err, appointment.ErrNotInSeries),
+		errors.Is(err, appointment.ErrOutsideAvailability),
+		errors.Is(err, service.ErrProfessionalNotOffering),
+		errors.Is(err, auth.ErrClientRequired),
+		errors.Is(err, auth.ErrInvalidClient):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrNotFound),
+		errors.Is(err, appointment.ErrSeriesNotFound),
+		errors.Is(err, appointment.ErrHoldNotFound),
+		errors.Is(err, service.ErrNotFound),
+		errors.Is(err, user.ErrNotFound),
+		errors.Is(err, user.ErrProfessionalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,9 +1,70 @@
 package appointment_handler
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/appointment"
+)
 
 type BookAppointmentRequest struct {
-	ServiceID uuid.UUID `json:"service_id"`
-	ClientID  uuid.UUID `json:"client_id"`
-	StartTime string    `json:"start_time"`
-}
\ No newline at end of file
+	ServiceID      uuid.UUID `json:"service_id" binding:"required"`
+	ProfessionalID uuid.UUID `json:"professional_id" binding:"required"`
+	StartTime      string    `json:"start_time" binding:"required"`
+
+	// ClientID é aceito apenas de chaves de API, que agendam em nome de um cliente
+	ClientID *uuid.UUID `json:"client_id"`
+	// HoldID converte a reserva temporária do cliente para o mesmo serviço, profissional e horário
+	HoldID *uuid.UUID `json:"hold_id"`
+}
+
+// PlaceHoldRequest reserva temporariamente o horário que começa em StartTime
+type PlaceHoldRequest struct {
+	ServiceID      uuid.UUID `json:"service_id" binding:"required"`
+	ProfessionalID uuid.UUID `json:"professional_id" binding:"required"`
+	StartTime      string    `json:"start_time" binding:"required"`
+
+	// ClientID é aceito apenas de chaves de API, que reservam em nome de um cliente
+	ClientID *uuid.UUID `json:"client_id"`
+}
+
+// BookSeriesRequest agenda uma série a partir da primeira ocorrência em StartTime
+type BookSeriesRequest struct {
+	ServiceID      uuid.UUID         `json:"service_id" binding:"required"`
+	ProfessionalID uuid.UUID         `json:"professional_id" binding:"required"`
+	StartTime      string            `json:"start_time" binding:"required"`
+	Recurrence     RecurrenceRequest `json:"recurrence" binding:"required"`
+	Mode           string            `json:"mode" binding:"omitempty,oneof=all_or_nothing book_available"`
+
+	// ClientID é aceito apenas de chaves de API, que agendam em nome de um cliente
+	ClientID *uuid.UUID `json:"client_id"`
+}
+
+type RecurrenceRequest struct {
+	Frequency string  `json:"frequency" binding:"required,oneof=weekly biweekly"`
+	Count     *int    `json:"count"`
+	Until     *string `json:"until"`
+}
+
+func (r *RecurrenceRequest) toDomain() appointment.Recurrence {
+	return appointment.Recurrence{
+		Frequency: r.Frequency,
+		Count:     r.Count,
+		Until:     r.Until,
+	}
+}
+
+type TransitionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// CancelRequest escolhe, em agendamentos de uma série, quais ocorrências cancelar
+type CancelRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+	Scope  string `json:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+// RescheduleRequest aceita um novo horário, um novo profissional ou ambos
+type RescheduleRequest struct {
+	StartTime      string     `json:"start_time"`
+	ProfessionalID *uuid.UUID `json:"professional_id"`
+	Scope          string     `json:"scope" binding:"omitempty,oneof=this following all"`
+}
@@ -1,20 +1,31 @@
 package auth_handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"youmeet/internal/adapters/handlers/middleware"
 	"youmeet/internal/core/domain/auth"
 	"youmeet/internal/core/services"
 )
 
 type Handler struct {
-	authService *services.AuthService
+	authService    *services.AuthService
+	accountService *services.AccountService
+	mfaService     *services.MFAService
+	oidcService    *services.OIDCService
 }
 
-func NewHandler(authService *services.AuthService) *Handler {
+func NewHandler(authService *services.AuthService, accountService *services.AccountService, mfaService *services.MFAService, oidcService *services.OIDCService) *Handler {
 	return &Handler{
-		authService: authService,
+		authService:    authService,
+		accountService: accountService,
+		mfaService:     mfaService,
+		oidcService:    oidcService,
 	}
 }
 
@@ -38,6 +49,11 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
+	// Falha no envio não impede o cadastro; o usuário pode pedir um novo link
+	if err := h.accountService.RequestEmailVerification(c.Request.Context(), user.ID); err != nil {
+		log.Printf("failed to send verification email to %s: %v", user.Email, err)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Usuário criado com sucesso",
 		"user": gin.H{
@@ -59,21 +75,287 @@ func (h *Handler) Login(c *gin.Context) {
 	authReq := &auth.AuthRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		IP:       c.ClientIP(),
 	}
 
 	response, err := h.authService.Login(c.Request.Context(), authReq)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		writeLoginError(c, err)
+		return
+	}
+
+	writeLoginResponse(c, response)
+}
+
+// OIDCLogin redireciona o usuário para a tela de autorização do provedor
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	authURL, err := h.oidcService.Begin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback recebe o retorno do provedor e responde como POST /auth/login
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.ErrExternalAuthFailed.Error(), "provider_error": providerErr})
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state and code are required"})
+		return
+	}
+
+	response, err := h.oidcService.Complete(c.Request.Context(), c.Param("provider"), state, code)
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	writeLoginResponse(c, response)
+}
+
+func writeLoginResponse(c *gin.Context, response *auth.AuthResponse) {
+	if response.MFARequired {
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required":    true,
+			"challenge_token": response.ChallengeToken,
+			"expires_at":      response.ExpiresAt,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse(response))
+}
+
+func writeOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrUnknownProvider):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrInvalidOAuthState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrExternalAuthFailed):
+		// O detalhe da falha fica apenas no log
+		log.Printf("oidc login failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.ErrExternalAuthFailed.Error()})
+	case errors.Is(err, auth.ErrExternalEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *Handler) VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.authService.VerifyMFA(c.Request.Context(), &auth.MFAVerifyRequest{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		IP:             c.ClientIP(),
+	})
+	if err != nil {
+		writeLoginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse(response))
+}
+
+func (h *Handler) EnrollMFA(c *gin.Context) {
+	identity := middleware.CurrentIdentity(c)
+
+	enrollment, err := h.mfaService.Enroll(c.Request.Context(), identity.User.ID)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *Handler) ConfirmMFA(c *gin.Context) {
+	var req MFAConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	codes, err := h.mfaService.Confirm(c.Request.Context(), identity.User.ID, req.Code)
+	if err != nil {
+		writeMFAError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": response.Token,
+		"message":        "Autenticação em dois fatores ativada",
+		"recovery_codes": codes,
+	})
+}
+
+func writeLoginError(c *gin.Context, err error) {
+	var throttleErr *auth.ThrottleError
+	switch {
+	case errors.As(err, &throttleErr):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttleErr.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrInvalidCredentials),
+		errors.Is(err, auth.ErrInvalidToken),
+		errors.Is(err, auth.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func writeMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, auth.ErrMFANotEnrolled),
+		errors.Is(err, auth.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *Handler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessionResponse(response))
+}
+
+func (h *Handler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) LogoutAll(c *gin.Context) {
+	identity := middleware.CurrentIdentity(c)
+
+	if err := h.authService.LogoutAll(c.Request.Context(), identity.User.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) RequestEmailVerification(c *gin.Context) {
+	identity := middleware.CurrentIdentity(c)
+
+	err := h.accountService.RequestEmailVerification(c.Request.Context(), identity.User.ID)
+	if err != nil {
+		if errors.Is(err, auth.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Email de verificação enviado"})
+}
+
+func (h *Handler) ConfirmEmail(c *gin.Context) {
+	var req ConfirmTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.ConfirmEmail(c.Request.Context(), req.Token); err != nil {
+		writeActionTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verificado com sucesso"})
+}
+
+func (h *Handler) RequestPasswordReset(c *gin.Context) {
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Mesma resposta para emails existentes ou não
+	c.JSON(http.StatusAccepted, gin.H{"message": "Se o email estiver cadastrado, você receberá um link de redefinição"})
+}
+
+func (h *Handler) ConfirmPasswordReset(c *gin.Context) {
+	var req PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.accountService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		writeActionTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Senha redefinida com sucesso"})
+}
+
+func writeActionTokenError(c *gin.Context, err error) {
+	if errors.Is(err, auth.ErrInvalidActionToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func sessionResponse(response *auth.AuthResponse) gin.H {
+	return gin.H{
+		"token":              response.Token,
+		"expires_at":         response.ExpiresAt,
+		"refresh_token":      response.RefreshToken,
+		"refresh_expires_at": response.RefreshExpiresAt,
 		"user": gin.H{
 			"id":    response.User.ID,
 			"name":  response.User.Name,
 			"email": response.User.Email,
 			"role":  response.User.Role,
 		},
-	})
-}
\ No newline at end of file
+	}
+}
@@ -0,0 +1,337 @@
+package availability_handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	availabilityService *services.AvailabilityService
+}
+
+func NewHandler(availabilityService *services.AvailabilityService) *Handler {
+	return &Handler{
+		availabilityService: availabilityService,
+	}
+}
+
+func (h *Handler) ListAvailability(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	availabilities, err := h.availabilityService.List(c.Request.Context(), professionalID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, availabilities)
+}
+
+func (h *Handler) CreateAvailability(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	var req AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	availability, err := h.availabilityService.Create(c.Request.Context(), professionalID, req.toDomain())
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, availability)
+}
+
+func (h *Handler) UpdateAvailability(c *gin.Context) {
+	professionalID, availabilityID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	availability, err := h.availabilityService.Update(c.Request.Context(), professionalID, availabilityID, req.toDomain())
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func (h *Handler) DeleteAvailability(c *gin.Context) {
+	professionalID, availabilityID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.availabilityService.Delete(c.Request.Context(), professionalID, availabilityID); err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListExceptions(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	var query ExceptionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Por padrão, as exceções de hoje em diante
+	if query.From.IsZero() {
+		query.From = time.Now()
+	}
+	if query.To.IsZero() {
+		query.To = query.From.AddDate(1, 0, 0)
+	}
+
+	exceptions, err := h.availabilityService.ListExceptions(c.Request.Context(), professionalID, query.From, query.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	for _, exception := range exceptions {
+		exception.Localize(loc)
+	}
+	c.JSON(http.StatusOK, exceptions)
+}
+
+func (h *Handler) CreateException(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	var req ExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exception, err := h.availabilityService.CreateException(c.Request.Context(), professionalID, &appointment.AvailabilityException{
+		Kind:      appointment.ExceptionKind(req.Kind),
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	exception.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusCreated, exception)
+}
+
+func (h *Handler) DeleteException(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+	exceptionID, err := uuid.Parse(c.Param("exceptionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid exception ID"})
+		return
+	}
+
+	if err := h.availabilityService.DeleteException(c.Request.Context(), professionalID, exceptionID); err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ListHolidays(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	holidays, err := h.availabilityService.ListHolidays(c.Request.Context(), companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+func (h *Handler) CreateHoliday(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	var req HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holiday, err := h.availabilityService.CreateHoliday(c.Request.Context(), companyID, &appointment.Holiday{
+		Date: req.Date,
+		Name: req.Name,
+	})
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+func (h *Handler) DeleteHoliday(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+	holidayID, err := uuid.Parse(c.Param("holidayId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid holiday ID"})
+		return
+	}
+
+	if err := h.availabilityService.DeleteHoliday(c.Request.Context(), companyID, holidayID); err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GetProfessionalTimeZone(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	timeZone, loc, err := h.availabilityService.GetProfessionalTimeZone(c.Request.Context(), professionalID)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_zone":           timeZone,
+		"effective_time_zone": loc.String(),
+	})
+}
+
+func (h *Handler) SetProfessionalTimeZone(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	var req TimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeZone, err := h.availabilityService.SetProfessionalTimeZone(c.Request.Context(), professionalID, *req.TimeZone)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_zone": timeZone})
+}
+
+func (h *Handler) SetCompanyTimeZone(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	var req TimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeZone, err := h.availabilityService.SetCompanyTimeZone(c.Request.Context(), companyID, *req.TimeZone)
+	if err != nil {
+		writeAvailabilityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_zone": timeZone})
+}
+
+func parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	availabilityID, err := uuid.Parse(c.Param("availabilityId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid availability ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return professionalID, availabilityID, true
+}
+
+func writeAvailabilityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, appointment.ErrInvalidAvailability),
+		errors.Is(err, appointment.ErrInvalidException),
+		errors.Is(err, appointment.ErrInvalidHoliday),
+		errors.Is(err, user.ErrInvalidTimeZone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrAvailabilityOverlap),
+		errors.Is(err, appointment.ErrHolidayAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrAvailabilityNotFound),
+		errors.Is(err, appointment.ErrExceptionNotFound),
+		errors.Is(err, appointment.ErrHolidayNotFound),
+		errors.Is(err, user.ErrProfessionalNotFound),
+		errors.Is(err, user.ErrCompanyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
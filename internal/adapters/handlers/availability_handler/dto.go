@@ -0,0 +1,56 @@
+package availability_handler
+
+import (
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+)
+
+type AvailabilityRequest struct {
+	DayOfWeek string         `json:"day_of_week" binding:"required"`
+	StartTime string         `json:"start_time" binding:"required"`
+	EndTime   string         `json:"end_time" binding:"required"`
+	Breaks    []BreakRequest `json:"breaks" binding:"dive"`
+}
+
+type BreakRequest struct {
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+func (r *AvailabilityRequest) toDomain() *appointment.Availability {
+	availability := &appointment.Availability{
+		DayOfWeek: appointment.DayOfWeek(r.DayOfWeek),
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+		Breaks:    []appointment.AvailabilityBreak{},
+	}
+	for _, b := range r.Breaks {
+		availability.Breaks = append(availability.Breaks, appointment.AvailabilityBreak{
+			StartTime: b.StartTime,
+			EndTime:   b.EndTime,
+		})
+	}
+	return availability
+}
+
+type ExceptionRequest struct {
+	Kind      string    `json:"kind" binding:"required"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required"`
+	Reason    string    `json:"reason"`
+}
+
+type ExceptionQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type TimeZoneRequest struct {
+	TimeZone *string `json:"time_zone" binding:"required"`
+}
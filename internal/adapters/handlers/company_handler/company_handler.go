@@ -0,0 +1,114 @@
+package company_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	mfaService    *services.MFAService
+	apiKeyService *services.APIKeyService
+}
+
+func NewHandler(mfaService *services.MFAService, apiKeyService *services.APIKeyService) *Handler {
+	return &Handler{
+		mfaService:    mfaService,
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *Handler) SetMFAPolicy(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	var req MFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.SetCompanyPolicy(c.Request.Context(), companyID, *req.RequireProfessionalMFA); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"require_professional_mfa": *req.RequireProfessionalMFA})
+}
+
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, raw, err := h.apiKeyService.Create(c.Request.Context(), companyID, req.Name, req.Scopes)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "valid_scopes": auth.ValidScopes})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A chave completa só é exibida nesta resposta
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     raw,
+	})
+}
+
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	keys, err := h.apiKeyService.List(c.Request.Context(), companyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), companyID, keyID); err != nil {
+		if errors.Is(err, auth.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
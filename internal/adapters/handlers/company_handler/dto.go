@@ -0,0 +1,10 @@
+package company_handler
+
+type MFAPolicyRequest struct {
+	RequireProfessionalMFA *bool `json:"require_professional_mfa" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"youmeet/internal/core/domain/auth"
+)
+
+const identityKey = "identity"
+
+type Auth struct {
+	authService auth.Service
+	apiKeys     auth.APIKeyAuthenticator
+	auditor     auth.ImpersonationAuditor
+}
+
+func NewAuth(authService auth.Service, apiKeys auth.APIKeyAuthenticator, auditor auth.ImpersonationAuditor) *Auth {
+	return &Auth{
+		authService: authService,
+		apiKeys:     apiKeys,
+		auditor:     auditor,
+	}
+}
+
+// Required exige um token Bearer válido e injeta a identidade no gin.Context e no context.Context.
+// Profissionais que ainda precisam cadastrar o 2FA exigido pela empresa recebem 403.
+func (m *Auth) Required() gin.HandlerFunc {
+	return m.authenticate(false)
+}
+
+// RequiredAllowingMFAEnrollment funciona como Required, mas aceita profissionais com
+// cadastro de 2FA pendente. Usado apenas nas rotas de cadastro do segundo fator.
+func (m *Auth) RequiredAllowingMFAEnrollment() gin.HandlerFunc {
+	return m.authenticate(true)
+}
+
+// RequiredOrAPIKey aceita, além dos tokens de usuário, chaves de API de empresas que
+// possuam o escopo informado. A chave pode vir no header X-API-Key ou como Bearer.
+func (m *Auth) RequiredOrAPIKey(scope string) gin.HandlerFunc {
+	userAuth := m.authenticate(false)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			if token, ok := bearerToken(c.GetHeader("Authorization")); ok && strings.HasPrefix(token, auth.APIKeyPrefix) {
+				key = token
+			}
+		}
+		if key == "" {
+			userAuth(c)
+			return
+		}
+
+		ctx := c.Request.Context()
+		identity, err := m.apiKeys.Authenticate(ctx, key)
+		if err != nil {
+			unauthorized(c, err.Error())
+			return
+		}
+		if !identity.APIKey.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrMissingScope.Error()})
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Request = c.Request.WithContext(auth.WithIdentity(ctx, identity))
+		c.Next()
+	}
+}
+
+func (m *Auth) authenticate(allowPendingMFA bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			unauthorized(c, "token de acesso ausente")
+			return
+		}
+
+		ctx := c.Request.Context()
+		identity, err := m.authService.Authenticate(ctx, token)
+		if err != nil {
+			unauthorized(c, err.Error())
+			return
+		}
+
+		// Requisições feitas durante uma personificação só prosseguem se forem auditadas
+		if identity.Impersonator != nil {
+			if err := m.auditor.RecordImpersonatedRequest(ctx, identity, c.Request.Method, c.Request.URL.Path, c.ClientIP()); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		if identity.MFAEnrollmentRequired && !allowPendingMFA {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrMFAEnrollmentNeeded.Error()})
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Request = c.Request.WithContext(auth.WithIdentity(ctx, identity))
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail bloqueia usuários com email não verificado quando enabled é verdadeiro.
+// Deve ser registrado depois de Auth.Required.
+func RequireVerifiedEmail(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		// Chaves de API agem em nome da empresa, não de um usuário final
+		identity := CurrentIdentity(c)
+		if identity != nil && identity.APIKey != nil {
+			c.Next()
+			return
+		}
+		if identity == nil || !identity.User.IsEmailVerified() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrEmailNotVerified.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RejectImpersonation bloqueia ações sobre credenciais e sessões quando a requisição
+// usa um token de personificação. Deve ser registrado depois de Auth.Required.
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := CurrentIdentity(c)
+		if identity != nil && identity.Impersonator != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrActionDuringImpersonation.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentIdentity retorna a identidade injetada por Required
+func CurrentIdentity(c *gin.Context) *auth.Identity {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	identity, _ := value.(*auth.Identity)
+	return identity
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", "Bearer")
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": message})
+}
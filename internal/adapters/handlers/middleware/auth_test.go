@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+)
+
+// fakeAuthService aceita apenas os tokens cadastrados em identities
+type fakeAuthService struct {
+	auth.Service
+	identities map[string]*auth.Identity
+}
+
+func (s *fakeAuthService) Authenticate(ctx context.Context, token string) (*auth.Identity, error) {
+	identity, ok := s.identities[token]
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	return identity, nil
+}
+
+// fakeAuditor guarda as requisições personificadas registradas e falha quando err é definido
+type fakeAuditor struct {
+	requests []string
+	err      error
+}
+
+func (a *fakeAuditor) RecordImpersonatedRequest(ctx context.Context, identity *auth.Identity, method, path, ip string) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.requests = append(a.requests, identity.Impersonator.ID.String()+" "+method+" "+path)
+	return nil
+}
+
+// fakeAPIKeys aceita apenas as chaves cadastradas em keys
+type fakeAPIKeys struct {
+	keys map[string]*auth.Identity
+}
+
+func (k *fakeAPIKeys) Authenticate(ctx context.Context, key string) (*auth.Identity, error) {
+	identity, ok := k.keys[key]
+	if !ok {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	return identity, nil
+}
+
+func TestRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client := &user.User{ID: uuid.New(), Role: "client"}
+	m := NewAuth(&fakeAuthService{identities: map[string]*auth.Identity{"token-valido": {User: client}}}, &fakeAPIKeys{}, &fakeAuditor{})
+
+	router := gin.New()
+	router.GET("/me", m.Required(), func(c *gin.Context) {
+		fromContext, ok := auth.IdentityFromContext(c.Request.Context())
+		if !ok || fromContext != CurrentIdentity(c) {
+			t.Error("a identidade não foi injetada no context.Context")
+		}
+		c.String(http.StatusOK, CurrentIdentity(c).User.ID.String())
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "sem header", status: http.StatusUnauthorized},
+		{name: "outro esquema", header: "Basic token-valido", status: http.StatusUnauthorized},
+		{name: "token inválido", header: "Bearer token-invalido", status: http.StatusUnauthorized},
+		{name: "token válido", header: "bearer token-valido", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, esperado %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Fatal("resposta 401 sem o header WWW-Authenticate")
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != client.ID.String() {
+				t.Fatalf("identidade = %s, esperado %s", rec.Body.String(), client.ID)
+			}
+		})
+	}
+}
+
+func TestRequiredOrAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client := &user.User{ID: uuid.New(), Role: "client"}
+	companyOwner := &user.User{ID: uuid.New(), Role: "company"}
+	m := NewAuth(
+		&fakeAuthService{identities: map[string]*auth.Identity{"token-valido": {User: client}}},
+		&fakeAPIKeys{keys: map[string]*auth.Identity{
+			"ym_escrita_segredo": {User: companyOwner, APIKey: &auth.APIKey{Scopes: []string{auth.ScopeAppointmentsWrite}}},
+			"ym_leitura_segredo": {User: companyOwner, APIKey: &auth.APIKey{Scopes: []string{auth.ScopeServicesRead}}},
+		}},
+		&fakeAuditor{},
+	)
+
+	router := gin.New()
+	router.POST("/appointments", m.RequiredOrAPIKey(auth.ScopeAppointmentsWrite), func(c *gin.Context) {
+		c.String(http.StatusOK, CurrentIdentity(c).User.ID.String())
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		status   int
+		identity uuid.UUID
+	}{
+		{name: "token de usuário", header: "Authorization", value: "Bearer token-valido", status: http.StatusOK, identity: client.ID},
+		{name: "chave no X-API-Key", header: "X-API-Key", value: "ym_escrita_segredo", status: http.StatusOK, identity: companyOwner.ID},
+		{name: "chave como Bearer", header: "Authorization", value: "Bearer ym_escrita_segredo", status: http.StatusOK, identity: companyOwner.ID},
+		{name: "chave sem o escopo", header: "X-API-Key", value: "ym_leitura_segredo", status: http.StatusForbidden},
+		{name: "chave inválida", header: "X-API-Key", value: "ym_outra_segredo", status: http.StatusUnauthorized},
+		{name: "sem credenciais", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/appointments", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, esperado %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.identity.String() {
+				t.Fatalf("identidade = %s, esperado %s", rec.Body.String(), tt.identity)
+			}
+		})
+	}
+}
+
+func TestImpersonatedRequestsAreAudited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := &user.User{ID: uuid.New(), Role: "admin"}
+	client := &user.User{ID: uuid.New(), Role: "client"}
+	auditor := &fakeAuditor{}
+	m := NewAuth(&fakeAuthService{identities: map[string]*auth.Identity{
+		"token-personificado": {User: client, Impersonator: admin},
+		"token-valido":        {User: client},
+	}}, &fakeAPIKeys{}, auditor)
+
+	router := gin.New()
+	router.GET("/appointments", m.Required(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/auth/logout", m.Required(), RejectImpersonation(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if status := serve(http.MethodGet, "/appointments", "token-valido"); status != http.StatusOK || len(auditor.requests) != 0 {
+		t.Fatalf("status = %d com %d registros, esperado 200 sem auditoria", status, len(auditor.requests))
+	}
+	if status := serve(http.MethodGet, "/appointments", "token-personificado"); status != http.StatusOK {
+		t.Fatalf("status = %d, esperado 200", status)
+	}
+	if want := admin.ID.String() + " GET /appointments"; len(auditor.requests) != 1 || auditor.requests[0] != want {
+		t.Fatalf("auditoria = %v, esperado [%s]", auditor.requests, want)
+	}
+
+	// Credenciais e sessões não podem ser alteradas durante a personificação
+	if status := serve(http.MethodPost, "/auth/logout", "token-personificado"); status != http.StatusForbidden {
+		t.Fatalf("status = %d, esperado 403", status)
+	}
+	if status := serve(http.MethodPost, "/auth/logout", "token-valido"); status != http.StatusNoContent {
+		t.Fatalf("status = %d, esperado 204", status)
+	}
+
+	// Sem o registro na auditoria a requisição não prossegue
+	auditor.err = errors.New("falha ao gravar")
+	if status := serve(http.MethodGet, "/appointments", "token-personificado"); status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, esperado 500", status)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+// Authorize aplica a política ao recurso identificado pelo parâmetro de rota param.
+// Use param vazio para políticas que não dependem de um recurso específico.
+// Deve ser registrado depois de Auth.Required.
+func Authorize(param string, policy services.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := CurrentIdentity(c)
+		if identity == nil {
+			unauthorized(c, "token de acesso ausente")
+			return
+		}
+
+		resourceID := uuid.Nil
+		if param != "" {
+			id, err := uuid.Parse(c.Param(param))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+				return
+			}
+			resourceID = id
+		}
+
+		if err := policy(c.Request.Context(), identity, resourceID); err != nil {
+			abortWithPolicyError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole permite acesso apenas às roles informadas
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return Authorize("", services.RequireRole(roles...))
+}
+
+func abortWithPolicyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": auth.ErrForbidden.Error()})
+	case errors.Is(err, appointment.ErrNotFound),
+		errors.Is(err, appointment.ErrSeriesNotFound),
+		errors.Is(err, appointment.ErrWaitlistNotFound),
+		errors.Is(err, appointment.ErrHoldNotFound),
+		errors.Is(err, user.ErrCompanyNotFound),
+		errors.Is(err, user.ErrProfessionalNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"youmeet/internal/core/domain/idempotency"
+	"youmeet/internal/core/services"
+)
+
+// Idempotency torna seguras as repetições de requisições com o header Idempotency-Key: a primeira é
+// processada e sua resposta gravada; repetições com o mesmo método, caminho e corpo recebem a resposta
+// gravada, com o header Idempotent-Replayed, e a mesma chave com outra requisição é recusada. Requisições
+// sem o header seguem normalmente. Deve ser registrado depois da autenticação, pois as chaves de cada
+// usuário ou chave de API são independentes.
+func Idempotency(service *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader("Idempotency-Key")
+		if clientKey == "" {
+			c.Next()
+			return
+		}
+		if len(clientKey) > idempotency.MaxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": idempotency.ErrInvalidKey.Error()})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := identityScope(c) + ":" + clientKey
+		ctx := c.Request.Context()
+		record, err := service.Begin(ctx, key, fingerprint(c.Request.Method, c.Request.URL.Path, body))
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		case record.Completed():
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		// A chave precisa ser liberada ou completada mesmo que o cliente desconecte no meio da requisição
+		ctx = context.WithoutCancel(ctx)
+		lease := record.LeaseToken
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			// Um pânico no handler não pode deixar a chave presa como "em andamento" até expirar
+			if !completed {
+				if err := service.Abandon(ctx, key, lease); err != nil {
+					log.Printf("idempotency: falha ao liberar a chave: %v", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		completed = true
+		if err := service.Complete(ctx, key, lease, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("idempotency: falha ao gravar a resposta: %v", err)
+		}
+	}
+}
+
+// fingerprint resume a requisição para reconhecer repetições idênticas
+func fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copia a resposta enviada ao cliente para que ela possa ser gravada
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
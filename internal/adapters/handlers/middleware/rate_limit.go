@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow conta os pedidos de um usuário ou chave de API na janela que começou em start
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit aceita até limit pedidos de cada usuário ou chave de API por janela de window e responde
+// 429 aos demais. A contagem fica em memória, então cada instância da API limita separadamente.
+// Deve ser registrado depois da autenticação.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var (
+		mu        sync.Mutex
+		windows   = map[string]*rateWindow{}
+		lastPrune = time.Now()
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		scope := identityScope(c)
+
+		mu.Lock()
+		// Descarta as janelas encerradas para a contagem não crescer com usuários que já pararam
+		if now.Sub(lastPrune) >= window {
+			for key, w := range windows {
+				if now.Sub(w.start) >= window {
+					delete(windows, key)
+				}
+			}
+			lastPrune = now
+		}
+
+		w, ok := windows[scope]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			windows[scope] = w
+		}
+		w.count++
+		exceeded := w.count > limit
+		retryAfter := w.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if exceeded {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "muitas requisições; tente novamente mais tarde"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// identityScope identifica a chave de API ou o usuário da requisição
+func identityScope(c *gin.Context) string {
+	identity := CurrentIdentity(c)
+	switch {
+	case identity == nil:
+		return "anonymous"
+	case identity.APIKey != nil:
+		return "api-key:" + identity.APIKey.ID.String()
+	default:
+		return "user:" + identity.User.ID.String()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRateLimitCountsEachIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := map[string]*user.User{"ana": {ID: uuid.New()}, "bruno": {ID: uuid.New()}}
+	router := gin.New()
+	router.POST("/holds", func(c *gin.Context) {
+		c.Set(identityKey, &auth.Identity{User: users[c.GetHeader("X-User")]})
+	}, RateLimit(2, time.Hour), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	request := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/holds", nil)
+		req.Header.Set("X-User", name)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := request("ana"); rec.Code != http.StatusCreated {
+			t.Fatalf("pedido %d: status = %d, esperado %d", i+1, rec.Code, http.StatusCreated)
+		}
+	}
+	rec := request("ana")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("status = %d com Retry-After %q, esperado %d com Retry-After", rec.Code, rec.Header().Get("Retry-After"), http.StatusTooManyRequests)
+	}
+
+	// Cada usuário tem a própria contagem
+	if rec := request("bruno"); rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, esperado %d", rec.Code, http.StatusCreated)
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"youmeet/internal/core/domain/user"
+)
+
+const locationKey = "location"
+
+// TimeZone lê o fuso em que o cliente quer receber os horários, informado pelo parâmetro
+// tz ou pelo header Time-Zone (ex.: America/Sao_Paulo). Sem nenhum dos dois, usa UTC.
+func TimeZone() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Query("tz")
+		if name == "" {
+			name = c.GetHeader("Time-Zone")
+		}
+
+		loc, err := user.LoadLocation(name)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(locationKey, loc)
+		c.Next()
+	}
+}
+
+// RequestLocation retorna o fuso escolhido pelo cliente, ou UTC quando TimeZone não foi registrado
+func RequestLocation(c *gin.Context) *time.Location {
+	value, ok := c.Get(locationKey)
+	if !ok {
+		return time.UTC
+	}
+	loc, _ := value.(*time.Location)
+	return loc
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeZone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/slots", TimeZone(), func(c *gin.Context) {
+		c.String(http.StatusOK, RequestLocation(c).String())
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		status int
+		want   string
+	}{
+		{name: "sem fuso", status: http.StatusOK, want: "UTC"},
+		{name: "parâmetro tz", query: "?tz=America/Sao_Paulo", status: http.StatusOK, want: "America/Sao_Paulo"},
+		{name: "header Time-Zone", header: "Europe/Lisbon", status: http.StatusOK, want: "Europe/Lisbon"},
+		{name: "parâmetro tem prioridade", query: "?tz=Asia/Tokyo", header: "Europe/Lisbon", status: http.StatusOK, want: "Asia/Tokyo"},
+		{name: "fuso inválido", query: "?tz=Marte/Olympus", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/slots"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Time-Zone", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, esperado %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.want {
+				t.Fatalf("fuso = %s, esperado %s", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
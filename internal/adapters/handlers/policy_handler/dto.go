@@ -0,0 +1,19 @@
+package policy_handler
+
+import "youmeet/internal/core/domain/appointment"
+
+type PolicyRequest struct {
+	MinNoticeMinutes      int     `json:"min_notice_minutes"`
+	MaxReschedules        *int    `json:"max_reschedules"`
+	AllowLateCancellation bool    `json:"allow_late_cancellation"`
+	LateCancellationFee   float64 `json:"late_cancellation_fee"`
+}
+
+func (r *PolicyRequest) toDomain() *appointment.Policy {
+	return &appointment.Policy{
+		MinNoticeMinutes:      r.MinNoticeMinutes,
+		MaxReschedules:        r.MaxReschedules,
+		AllowLateCancellation: r.AllowLateCancellation,
+		LateCancellationFee:   r.LateCancellationFee,
+	}
+}
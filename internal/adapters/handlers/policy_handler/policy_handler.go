@@ -0,0 +1,111 @@
+package policy_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	policyService *services.PolicyService
+}
+
+func NewHandler(policyService *services.PolicyService) *Handler {
+	return &Handler{
+		policyService: policyService,
+	}
+}
+
+func (h *Handler) GetCompanyPolicy(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	policy, err := h.policyService.GetCompanyPolicy(c.Request.Context(), companyID)
+	if err != nil {
+		writePolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *Handler) SetCompanyPolicy(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid company ID"})
+		return
+	}
+
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.policyService.SetCompanyPolicy(c.Request.Context(), companyID, req.toDomain())
+	if err != nil {
+		writePolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *Handler) GetProfessionalPolicy(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	policy, err := h.policyService.GetProfessionalPolicy(c.Request.Context(), professionalID)
+	if err != nil {
+		writePolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *Handler) SetProfessionalPolicy(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	var req PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.policyService.SetProfessionalPolicy(c.Request.Context(), professionalID, req.toDomain())
+	if err != nil {
+		writePolicyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func writePolicyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, appointment.ErrInvalidPolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrPolicyManagedByCompany):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrProfessionalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,9 @@
+package service_handler
+
+import "time"
+
+type SlotQuery struct {
+	From           time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	ProfessionalID string    `form:"professional_id" binding:"omitempty,uuid"`
+}
@@ -0,0 +1,79 @@
+package service_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	catalogService *services.CatalogService
+	slotService    *services.SlotService
+}
+
+func NewHandler(catalogService *services.CatalogService, slotService *services.SlotService) *Handler {
+	return &Handler{
+		catalogService: catalogService,
+		slotService:    slotService,
+	}
+}
+
+func (h *Handler) ListServices(c *gin.Context) {
+	list, err := h.catalogService.ListServices(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *Handler) ListSlots(c *gin.Context) {
+	serviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+		return
+	}
+
+	var query SlotQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var professionalID *uuid.UUID
+	if query.ProfessionalID != "" {
+		id, err := uuid.Parse(query.ProfessionalID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+			return
+		}
+		professionalID = &id
+	}
+
+	slots, err := h.slotService.FindSlots(c.Request.Context(), serviceID, professionalID, query.From, query.To)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrInvalidRange),
+			errors.Is(err, service.ErrProfessionalNotOffering):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	for _, slot := range slots {
+		slot.Localize(loc)
+	}
+	c.JSON(http.StatusOK, slots)
+}
@@ -0,0 +1,11 @@
+package waitlist_handler
+
+import "github.com/google/uuid"
+
+// JoinWaitlistRequest pede um horário do serviço com o profissional que comece e termine entre From e To
+type JoinWaitlistRequest struct {
+	ServiceID      uuid.UUID `json:"service_id" binding:"required"`
+	ProfessionalID uuid.UUID `json:"professional_id" binding:"required"`
+	From           string    `json:"from" binding:"required"`
+	To             string    `json:"to" binding:"required"`
+}
@@ -0,0 +1,161 @@
+package waitlist_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"youmeet/internal/adapters/handlers/middleware"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+type Handler struct {
+	waitlistService *services.WaitlistService
+}
+
+func NewHandler(waitlistService *services.WaitlistService) *Handler {
+	return &Handler{
+		waitlistService: waitlistService,
+	}
+}
+
+// Join coloca o cliente autenticado na lista de espera
+func (h *Handler) Join(c *gin.Context) {
+	var req JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity := middleware.CurrentIdentity(c)
+	entry, err := h.waitlistService.Join(c.Request.Context(), identity.User.ID, req.ServiceID, req.ProfessionalID, req.From, req.To)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	entry.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ListMine lista as entradas do cliente autenticado, com as ofertas em aberto
+func (h *Handler) ListMine(c *gin.Context) {
+	entries, err := h.waitlistService.ListByClient(c.Request.Context(), middleware.CurrentIdentity(c).User.ID)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	localize(c, entries)
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handler) GetEntry(c *gin.Context) {
+	id, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.waitlistService.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	entry.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, entry)
+}
+
+// Leave tira o cliente da lista de espera
+func (h *Handler) Leave(c *gin.Context) {
+	id, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.waitlistService.Leave(c.Request.Context(), id); err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Accept agenda o horário oferecido à entrada
+func (h *Handler) Accept(c *gin.Context) {
+	id, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	appt, err := h.waitlistService.Accept(c.Request.Context(), id)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	appt.Localize(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, appt)
+}
+
+// ListProfessionalWaitlist lista a fila de espera do profissional em ordem de chegada
+func (h *Handler) ListProfessionalWaitlist(c *gin.Context) {
+	professionalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid professional ID"})
+		return
+	}
+
+	entries, err := h.waitlistService.ListByProfessional(c.Request.Context(), professionalID)
+	if err != nil {
+		writeWaitlistError(c, err)
+		return
+	}
+
+	localize(c, entries)
+	c.JSON(http.StatusOK, entries)
+}
+
+func parseEntryID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid waitlist entry ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// localize exibe os horários no fuso pedido pelo cliente
+func localize(c *gin.Context, entries []*appointment.WaitlistEntry) {
+	loc := middleware.RequestLocation(c)
+	for _, e := range entries {
+		e.Localize(loc)
+	}
+}
+
+func writeWaitlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, appointment.ErrAlreadyWaitlisted),
+		errors.Is(err, appointment.ErrNoOffer),
+		errors.Is(err, appointment.ErrHoldExpired),
+		errors.Is(err, appointment.ErrSlotHeld),
+		errors.Is(err, appointment.ErrConflict),
+		errors.Is(err, appointment.ErrSessionFull),
+		errors.Is(err, appointment.ErrAlreadyAttending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrInvalidWaitlist),
+		errors.Is(err, appointment.ErrOutsideAvailability),
+		errors.Is(err, service.ErrProfessionalNotOffering):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, appointment.ErrWaitlistNotFound),
+		errors.Is(err, service.ErrNotFound),
+		errors.Is(err, user.ErrProfessionalNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
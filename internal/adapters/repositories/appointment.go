@@ -2,8 +2,12 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 	"github.com/google/uuid"
 	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
 )
 
 type AppointmentRepository struct {
@@ -15,27 +19,140 @@ func NewAppointmentRepository(db DBClient) *AppointmentRepository {
 }
 
 func (r *AppointmentRepository) CreateAppointment(ctx context.Context, appt *appointment.Appointment) error {
-	return r.db.Create(appt)
+	return conn(ctx, r.db).Create(appt)
 }
 
 func (r *AppointmentRepository) GetAppointmentByID(ctx context.Context, id uuid.UUID) (*appointment.Appointment, error) {
 	var appt appointment.Appointment
-	err := r.db.First(&appt, "id = ?", id)
-	return &appt, err
+	if err := conn(ctx, r.db).Preload("StatusHistory").Preload("RescheduleHistory").First(&appt, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrNotFound)
+	}
+	sort.Slice(appt.StatusHistory, func(i, j int) bool {
+		return appt.StatusHistory[i].CreatedAt.Before(appt.StatusHistory[j].CreatedAt)
+	})
+	sort.Slice(appt.RescheduleHistory, func(i, j int) bool {
+		return appt.RescheduleHistory[i].CreatedAt.Before(appt.RescheduleHistory[j].CreatedAt)
+	})
+	return &appt, nil
 }
 
 func (r *AppointmentRepository) ListAppointments(ctx context.Context, clientID uuid.UUID) ([]*appointment.Appointment, error) {
 	var appointments []*appointment.Appointment
-	err := r.db.Find(&appointments, "client_id = ?", clientID)
+	err := conn(ctx, r.db).Find(&appointments, "client_id = ?", clientID)
 	return appointments, err
 }
 
 func (r *AppointmentRepository) ListByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*appointment.Appointment, error) {
 	var appointments []*appointment.Appointment
-	err := r.db.Find(&appointments, "professional_id = ?", professionalID)
+	err := conn(ctx, r.db).Find(&appointments, "professional_id = ?", professionalID)
 	return appointments, err
 }
 
+func (r *AppointmentRepository) ListByCompany(ctx context.Context, companyID uuid.UUID) ([]*appointment.Appointment, error) {
+	var appointments []*appointment.Appointment
+	err := conn(ctx, r.db).Find(&appointments, "professional_id IN (SELECT id FROM professionals WHERE company_id = ?)", companyID)
+	return appointments, err
+}
+
+// LockProfessionalSchedule bloqueia a linha do profissional; no SQLite as transações já são serializadas
+func (r *AppointmentRepository) LockProfessionalSchedule(ctx context.Context, professionalID uuid.UUID) error {
+	var professional user.Professional
+	err := conn(ctx, r.db).ForUpdate().First(&professional, "id = ?", professionalID)
+	return translateNotFound(err, user.ErrProfessionalNotFound)
+}
+
+func (r *AppointmentRepository) HasConflict(ctx context.Context, professionalID uuid.UUID, start, end time.Time, excludeID uuid.UUID) (bool, error) {
+	var appointments []*appointment.Appointment
+	err := conn(ctx, r.db).
+		Where("professional_id = ? AND status NOT IN ? AND id <> ?", professionalID, appointment.FreeingStatuses, excludeID).
+		Where("start_time < ? AND end_time > ?", end.UTC(), start.UTC()).
+		Limit(1).
+		Find(&appointments)
+	return len(appointments) > 0, err
+}
+
+func (r *AppointmentRepository) ListActiveInRange(ctx context.Context, professionalIDs []uuid.UUID, from, to time.Time) ([]*appointment.Appointment, error) {
+	var appointments []*appointment.Appointment
+	err := conn(ctx, r.db).
+		Where("professional_id IN ? AND status NOT IN ?", professionalIDs, appointment.FreeingStatuses).
+		Where("start_time < ? AND end_time > ?", to.UTC(), from.UTC()).
+		Order("start_time").
+		Find(&appointments)
+	return appointments, err
+}
+
+func (r *AppointmentRepository) ChangeStatus(ctx context.Context, change *appointment.StatusChange) error {
+	rows, err := conn(ctx, r.db).
+		Where("id = ? AND status = ?", change.AppointmentID, change.FromStatus).
+		Updates(&appointment.Appointment{}, map[string]interface{}{
+			"status":            change.ToStatus,
+			"status_changed_at": change.CreatedAt,
+		})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: o agendamento foi alterado por outra requisição", appointment.ErrInvalidTransition)
+	}
+	return r.AddStatusChange(ctx, change)
+}
+
+func (r *AppointmentRepository) AddStatusChange(ctx context.Context, change *appointment.StatusChange) error {
+	return conn(ctx, r.db).Create(change)
+}
+
+func (r *AppointmentRepository) RecordLateCancellation(ctx context.Context, id uuid.UUID, fee float64) error {
+	_, err := conn(ctx, r.db).Where("id = ?", id).Updates(&appointment.Appointment{}, map[string]interface{}{
+		"late_cancellation": true,
+		"cancellation_fee":  fee,
+	})
+	return err
+}
+
+func (r *AppointmentRepository) UpdateSchedule(ctx context.Context, appt *appointment.Appointment) error {
+	_, err := conn(ctx, r.db).Where("id = ?", appt.ID).Updates(&appointment.Appointment{}, map[string]interface{}{
+		"professional_id":  appt.ProfessionalID,
+		"start_time":       appt.StartTime.UTC(),
+		"end_time":         appt.EndTime.UTC(),
+		"reschedule_count": appt.RescheduleCount,
+	})
+	return err
+}
+
+func (r *AppointmentRepository) AddReschedule(ctx context.Context, reschedule *appointment.Reschedule) error {
+	return conn(ctx, r.db).Create(reschedule)
+}
+
+func (r *AppointmentRepository) CreateSeries(ctx context.Context, series *appointment.Series) error {
+	return conn(ctx, r.db).Create(series)
+}
+
+func (r *AppointmentRepository) GetSeriesByID(ctx context.Context, id uuid.UUID) (*appointment.Series, error) {
+	var series appointment.Series
+	if err := conn(ctx, r.db).First(&series, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrSeriesNotFound)
+	}
+	appointments, err := r.ListBySeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	series.Appointments = appointments
+	return &series, nil
+}
+
+func (r *AppointmentRepository) ListBySeries(ctx context.Context, seriesID uuid.UUID) ([]*appointment.Appointment, error) {
+	var appointments []*appointment.Appointment
+	err := conn(ctx, r.db).Order("start_time").Find(&appointments, "series_id = ?", seriesID)
+	return appointments, err
+}
+
+func (r *AppointmentRepository) SetSeriesProfessional(ctx context.Context, seriesID, professionalID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Where("id = ?", seriesID).Updates(&appointment.Series{}, map[string]interface{}{
+		"professional_id": professionalID,
+	})
+	return err
+}
+
 type AvailabilityRepository struct {
 	db DBClient
 }
@@ -45,11 +162,44 @@ func NewAvailabilityRepository(db DBClient) *AvailabilityRepository {
 }
 
 func (r *AvailabilityRepository) CreateAvailability(ctx context.Context, availability *appointment.Availability) error {
-	return r.db.Create(availability)
+	return conn(ctx, r.db).Create(availability)
+}
+
+func (r *AvailabilityRepository) GetAvailabilityByID(ctx context.Context, id uuid.UUID) (*appointment.Availability, error) {
+	var availability appointment.Availability
+	if err := conn(ctx, r.db).Preload("Breaks").First(&availability, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrAvailabilityNotFound)
+	}
+	return &availability, nil
 }
 
 func (r *AvailabilityRepository) GetByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*appointment.Availability, error) {
 	var availabilities []*appointment.Availability
-	err := r.db.Find(&availabilities, "professional_id = ?", professionalID)
+	err := conn(ctx, r.db).Preload("Breaks").Order("start_time").Find(&availabilities, "professional_id = ?", professionalID)
+	return availabilities, err
+}
+
+func (r *AvailabilityRepository) ListByProfessionals(ctx context.Context, professionalIDs []uuid.UUID) ([]*appointment.Availability, error) {
+	var availabilities []*appointment.Availability
+	err := conn(ctx, r.db).Preload("Breaks").Find(&availabilities, "professional_id IN ?", professionalIDs)
 	return availabilities, err
-}
\ No newline at end of file
+}
+
+// UpdateAvailability substitui a janela e todas as suas pausas
+func (r *AvailabilityRepository) UpdateAvailability(ctx context.Context, availability *appointment.Availability) error {
+	return conn(ctx, r.db).Transaction(func(tx DBClient) error {
+		if err := tx.Delete(&appointment.AvailabilityBreak{}, "availability_id = ?", availability.ID); err != nil {
+			return err
+		}
+		return tx.Save(availability)
+	})
+}
+
+func (r *AvailabilityRepository) DeleteAvailability(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Transaction(func(tx DBClient) error {
+		if err := tx.Delete(&appointment.AvailabilityBreak{}, "availability_id = ?", id); err != nil {
+			return err
+		}
+		return tx.Delete(&appointment.Availability{}, "id = ?", id)
+	})
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"youmeet/internal/core/domain/audit"
+)
+
+type AuditRepository struct {
+	db DBClient
+}
+
+func NewAuditRepository(db DBClient) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, log *audit.AuditLog) error {
+	return conn(ctx, r.db).Create(log)
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter) ([]*audit.AuditLog, error) {
+	query := conn(ctx, r.db)
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.SubjectID != nil {
+		query = query.Where("subject_id = ?", *filter.SubjectID)
+	}
+
+	var logs []*audit.AuditLog
+	err := query.Order("created_at DESC").Limit(filter.Limit).Find(&logs)
+	return logs, err
+}
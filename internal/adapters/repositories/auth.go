@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/auth"
+)
+
+type RefreshTokenRepository struct {
+	db DBClient
+}
+
+func NewRefreshTokenRepository(db DBClient) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *auth.RefreshToken) error {
+	return conn(ctx, r.db).Create(token)
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	var token auth.RefreshToken
+	if err := conn(ctx, r.db).First(&token, "token_hash = ?", tokenHash); err != nil {
+		return nil, translateNotFound(err, auth.ErrInvalidRefreshToken)
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id uuid.UUID, replacedByID *uuid.UUID) (bool, error) {
+	affected, err := conn(ctx, r.db).Where("id = ? AND revoked_at IS NULL", id).Updates(&auth.RefreshToken{}, map[string]interface{}{
+		"revoked_at":     time.Now(),
+		"replaced_by_id": replacedByID,
+	})
+	return affected == 1, err
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Where("family_id = ? AND revoked_at IS NULL", familyID).Updates(&auth.RefreshToken{}, map[string]interface{}{
+		"revoked_at": time.Now(),
+	})
+	return err
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Where("user_id = ? AND revoked_at IS NULL", userID).Updates(&auth.RefreshToken{}, map[string]interface{}{
+		"revoked_at": time.Now(),
+	})
+	return err
+}
+
+type ActionTokenRepository struct {
+	db DBClient
+}
+
+func NewActionTokenRepository(db DBClient) *ActionTokenRepository {
+	return &ActionTokenRepository{db: db}
+}
+
+func (r *ActionTokenRepository) Create(ctx context.Context, token *auth.ActionToken) error {
+	return conn(ctx, r.db).Create(token)
+}
+
+func (r *ActionTokenRepository) GetByHash(ctx context.Context, purpose, tokenHash string) (*auth.ActionToken, error) {
+	var token auth.ActionToken
+	if err := conn(ctx, r.db).First(&token, "purpose = ? AND token_hash = ?", purpose, tokenHash); err != nil {
+		return nil, translateNotFound(err, auth.ErrInvalidActionToken)
+	}
+	return &token, nil
+}
+
+func (r *ActionTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	affected, err := conn(ctx, r.db).Where("id = ? AND used_at IS NULL", id).Updates(&auth.ActionToken{}, map[string]interface{}{
+		"used_at": time.Now(),
+	})
+	return affected == 1, err
+}
+
+func (r *ActionTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	_, err := conn(ctx, r.db).Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).Updates(&auth.ActionToken{}, map[string]interface{}{
+		"used_at": time.Now(),
+	})
+	return err
+}
+
+type LoginThrottleRepository struct {
+	db DBClient
+}
+
+func NewLoginThrottleRepository(db DBClient) *LoginThrottleRepository {
+	return &LoginThrottleRepository{db: db}
+}
+
+func (r *LoginThrottleRepository) Get(ctx context.Context, key string) (*auth.LoginThrottle, error) {
+	var throttle auth.LoginThrottle
+	if err := conn(ctx, r.db).First(&throttle, "throttle_key = ?", key); err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return &auth.LoginThrottle{Key: key}, nil
+		}
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *LoginThrottleRepository) Update(ctx context.Context, key string, fn func(throttle *auth.LoginThrottle)) (*auth.LoginThrottle, error) {
+	var throttle auth.LoginThrottle
+	err := conn(ctx, r.db).Transaction(func(tx DBClient) error {
+		// A linha é criada antes do bloqueio para que a primeira falha também seja serializada
+		if err := tx.IgnoreConflicts().Create(&auth.LoginThrottle{Key: key}); err != nil {
+			return err
+		}
+		if err := tx.ForUpdate().First(&throttle, "throttle_key = ?", key); err != nil {
+			return err
+		}
+		fn(&throttle)
+		return tx.Save(&throttle)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *LoginThrottleRepository) Reset(ctx context.Context, key string) error {
+	return conn(ctx, r.db).Delete(&auth.LoginThrottle{}, "throttle_key = ?", key)
+}
+
+type RecoveryCodeRepository struct {
+	db DBClient
+}
+
+func NewRecoveryCodeRepository(db DBClient) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []*auth.RecoveryCode) error {
+	if err := conn(ctx, r.db).Delete(&auth.RecoveryCode{}, "user_id = ?", userID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Create(codes)
+}
+
+func (r *RecoveryCodeRepository) Use(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	affected, err := conn(ctx, r.db).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).Updates(&auth.RecoveryCode{}, map[string]interface{}{
+		"used_at": time.Now(),
+	})
+	return affected == 1, err
+}
+
+type APIKeyRepository struct {
+	db DBClient
+}
+
+func NewAPIKeyRepository(db DBClient) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *auth.APIKey) error {
+	return conn(ctx, r.db).Create(key)
+}
+
+func (r *APIKeyRepository) ListByCompany(ctx context.Context, companyID uuid.UUID) ([]*auth.APIKey, error) {
+	var keys []*auth.APIKey
+	err := conn(ctx, r.db).Find(&keys, "company_id = ?", companyID)
+	return keys, err
+}
+
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*auth.APIKey, error) {
+	var key auth.APIKey
+	if err := conn(ctx, r.db).First(&key, "prefix = ?", prefix); err != nil {
+		return nil, translateNotFound(err, auth.ErrInvalidAPIKey)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, companyID, id uuid.UUID) (bool, error) {
+	affected, err := conn(ctx, r.db).Where("id = ? AND company_id = ? AND revoked_at IS NULL", id, companyID).Updates(&auth.APIKey{}, map[string]interface{}{
+		"revoked_at": time.Now(),
+	})
+	return affected == 1, err
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	_, err := conn(ctx, r.db).Where("id = ?", id).Updates(&auth.APIKey{}, map[string]interface{}{
+		"last_used_at": usedAt,
+	})
+	return err
+}
+
+type UserIdentityRepository struct {
+	db DBClient
+}
+
+func NewUserIdentityRepository(db DBClient) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *auth.UserIdentity) error {
+	return conn(ctx, r.db).Create(identity)
+}
+
+func (r *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*auth.UserIdentity, error) {
+	var identity auth.UserIdentity
+	if err := conn(ctx, r.db).First(&identity, "provider = ? AND subject = ?", provider, subject); err != nil {
+		return nil, translateNotFound(err, auth.ErrIdentityNotLinked)
+	}
+	return &identity, nil
+}
+
+type OAuthStateRepository struct {
+	db DBClient
+}
+
+func NewOAuthStateRepository(db DBClient) *OAuthStateRepository {
+	return &OAuthStateRepository{db: db}
+}
+
+func (r *OAuthStateRepository) Create(ctx context.Context, state *auth.OAuthState) error {
+	return conn(ctx, r.db).Create(state)
+}
+
+func (r *OAuthStateRepository) GetByHash(ctx context.Context, stateHash string) (*auth.OAuthState, error) {
+	var state auth.OAuthState
+	if err := conn(ctx, r.db).First(&state, "state_hash = ?", stateHash); err != nil {
+		return nil, translateNotFound(err, auth.ErrInvalidOAuthState)
+	}
+	return &state, nil
+}
+
+func (r *OAuthStateRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	affected, err := conn(ctx, r.db).Where("id = ? AND used_at IS NULL", id).Updates(&auth.OAuthState{}, map[string]interface{}{
+		"used_at": time.Now(),
+	})
+	return affected == 1, err
+}
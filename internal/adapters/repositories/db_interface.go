@@ -1,11 +1,48 @@
 package repositories
 
+import "errors"
+
+var (
+	// ErrRecordNotFound é retornado pelos clientes quando First não encontra registros
+	ErrRecordNotFound = errors.New("registro não encontrado")
+	// ErrDuplicateKey é retornado pelos clientes quando Create viola uma chave primária ou um índice único
+	ErrDuplicateKey = errors.New("registro com a mesma chave já existe")
+)
+
 // DBClient interface genérica para operações de banco de dados
 type DBClient interface {
 	Create(value interface{}) error
+	Save(value interface{}) error
 	First(dest interface{}, conds ...interface{}) error
 	Find(dest interface{}, conds ...interface{}) error
 	Where(query interface{}, args ...interface{}) DBClient
+	Order(value interface{}) DBClient
+	Limit(limit int) DBClient
+	// Preload carrega a associação informada junto com os registros
+	Preload(query string, args ...interface{}) DBClient
+	// ForUpdate bloqueia as linhas lidas até o fim da transação (SELECT ... FOR UPDATE)
+	ForUpdate() DBClient
+	// IgnoreConflicts faz Create ignorar registros cuja chave já existe (ON CONFLICT DO NOTHING)
+	IgnoreConflicts() DBClient
 	Delete(value interface{}, conds ...interface{}) error
+	Updates(model interface{}, values interface{}) (int64, error)
 	AutoMigrate(dst ...interface{}) error
-}
\ No newline at end of file
+	// Transaction executa fn em uma transação, confirmada apenas se fn não retornar erro
+	Transaction(fn func(tx DBClient) error) error
+}
+
+// translateNotFound converte ErrRecordNotFound no erro do domínio correspondente
+func translateNotFound(err error, domainErr error) error {
+	if errors.Is(err, ErrRecordNotFound) {
+		return domainErr
+	}
+	return err
+}
+
+// translateDuplicate converte ErrDuplicateKey no erro do domínio correspondente
+func translateDuplicate(err error, domainErr error) error {
+	if errors.Is(err, ErrDuplicateKey) {
+		return domainErr
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/appointment"
+)
+
+type ExceptionRepository struct {
+	db DBClient
+}
+
+func NewExceptionRepository(db DBClient) *ExceptionRepository {
+	return &ExceptionRepository{db: db}
+}
+
+func (r *ExceptionRepository) CreateException(ctx context.Context, exception *appointment.AvailabilityException) error {
+	return conn(ctx, r.db).Create(exception)
+}
+
+func (r *ExceptionRepository) GetExceptionByID(ctx context.Context, id uuid.UUID) (*appointment.AvailabilityException, error) {
+	var exception appointment.AvailabilityException
+	if err := conn(ctx, r.db).First(&exception, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrExceptionNotFound)
+	}
+	return &exception, nil
+}
+
+func (r *ExceptionRepository) DeleteException(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Delete(&appointment.AvailabilityException{}, "id = ?", id)
+}
+
+func (r *ExceptionRepository) ListExceptions(ctx context.Context, professionalIDs []uuid.UUID, from, to time.Time) ([]*appointment.AvailabilityException, error) {
+	var exceptions []*appointment.AvailabilityException
+	err := conn(ctx, r.db).
+		Where("professional_id IN ? AND start_time < ? AND end_time > ?", professionalIDs, to.UTC(), from.UTC()).
+		Order("start_time").
+		Find(&exceptions)
+	return exceptions, err
+}
+
+func (r *ExceptionRepository) CreateHoliday(ctx context.Context, holiday *appointment.Holiday) error {
+	// Cadastros simultâneos da mesma data passam juntos pela verificação do serviço e esbarram no índice único
+	return translateDuplicate(conn(ctx, r.db).Create(holiday), appointment.ErrHolidayAlreadyExists)
+}
+
+func (r *ExceptionRepository) GetHolidayByID(ctx context.Context, id uuid.UUID) (*appointment.Holiday, error) {
+	var holiday appointment.Holiday
+	if err := conn(ctx, r.db).First(&holiday, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrHolidayNotFound)
+	}
+	return &holiday, nil
+}
+
+func (r *ExceptionRepository) DeleteHoliday(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Delete(&appointment.Holiday{}, "id = ?", id)
+}
+
+func (r *ExceptionRepository) ListCompanyHolidays(ctx context.Context, companyID uuid.UUID) ([]*appointment.Holiday, error) {
+	var holidays []*appointment.Holiday
+	err := conn(ctx, r.db).Order("date").Find(&holidays, "company_id = ?", companyID)
+	return holidays, err
+}
+
+func (r *ExceptionRepository) ListHolidays(ctx context.Context, companyIDs []uuid.UUID, fromDate, toDate string) ([]*appointment.Holiday, error) {
+	var holidays []*appointment.Holiday
+	err := conn(ctx, r.db).Find(&holidays, "company_id IN ? AND date >= ? AND date <= ?", companyIDs, fromDate, toDate)
+	return holidays, err
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"youmeet/internal/core/domain/idempotency"
+)
+
+type IdempotencyRepository struct {
+	db DBClient
+}
+
+func NewIdempotencyRepository(db DBClient) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (*idempotency.Record, error) {
+	var record idempotency.Record
+	if err := conn(ctx, r.db).First(&record, "idempotency_key = ?", key); err != nil {
+		return nil, translateNotFound(err, idempotency.ErrNotFound)
+	}
+	return &record, nil
+}
+
+// Create confia na chave primária para que apenas uma de duas requisições simultâneas grave a chave
+func (r *IdempotencyRepository) Create(ctx context.Context, record *idempotency.Record) error {
+	return translateDuplicate(conn(ctx, r.db).Create(record), idempotency.ErrKeyExists)
+}
+
+// TakeOver só atualiza o registro se ele ainda estiver em andamento e vencido, para que apenas uma de
+// duas repetições simultâneas assuma a chave
+func (r *IdempotencyRepository) TakeOver(ctx context.Context, key, leaseToken string, lockedUntil, now time.Time) (bool, error) {
+	updated, err := conn(ctx, r.db).
+		Where("idempotency_key = ? AND status_code = 0", key).
+		Where("(locked_until IS NULL OR locked_until <= ?)", now.UTC()).
+		Updates(&idempotency.Record{}, map[string]interface{}{
+			"locked_until": lockedUntil.UTC(),
+			"lease_token":  leaseToken,
+		})
+	return updated == 1, err
+}
+
+// Complete só vale para quem ainda detém a chave, para que uma requisição cuja chave foi assumida por uma
+// repetição não sobrescreva a resposta dela
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *idempotency.Record) error {
+	_, err := conn(ctx, r.db).Where("idempotency_key = ? AND lease_token = ? AND status_code = 0", record.Key, record.LeaseToken).Updates(&idempotency.Record{}, map[string]interface{}{
+		"status_code":  record.StatusCode,
+		"content_type": record.ContentType,
+		"body":         record.Body,
+	})
+	return err
+}
+
+// Release também só vale para quem detém a chave, para não apagar a requisição de uma repetição que a assumiu
+func (r *IdempotencyRepository) Release(ctx context.Context, key, leaseToken string) error {
+	return conn(ctx, r.db).Delete(&idempotency.Record{}, "idempotency_key = ? AND lease_token = ? AND status_code = 0", key, leaseToken)
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, key string) error {
+	return conn(ctx, r.db).Delete(&idempotency.Record{}, "idempotency_key = ?", key)
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return conn(ctx, r.db).Delete(&idempotency.Record{}, "expires_at <= ?", now.UTC())
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"youmeet/internal/core/domain/appointment"
+)
+
+type PolicyRepository struct {
+	db DBClient
+}
+
+func NewPolicyRepository(db DBClient) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+func (r *PolicyRepository) GetPolicy(ctx context.Context, ownerID uuid.UUID) (*appointment.Policy, error) {
+	var policy appointment.Policy
+	if err := conn(ctx, r.db).First(&policy, "owner_id = ?", ownerID); err != nil {
+		return nil, translateNotFound(err, appointment.ErrPolicyNotFound)
+	}
+	return &policy, nil
+}
+
+func (r *PolicyRepository) SavePolicy(ctx context.Context, policy *appointment.Policy) error {
+	return conn(ctx, r.db).Save(policy)
+}
@@ -15,17 +15,19 @@ func NewServiceRepository(db DBClient) *ServiceRepository {
 }
 
 func (r *ServiceRepository) CreateService(ctx context.Context, svc *service.Service) error {
-	return r.db.Create(svc)
+	return conn(ctx, r.db).Create(svc)
 }
 
 func (r *ServiceRepository) GetServiceByID(ctx context.Context, id uuid.UUID) (*service.Service, error) {
 	var svc service.Service
-	err := r.db.First(&svc, "id = ?", id)
-	return &svc, err
+	if err := conn(ctx, r.db).First(&svc, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, service.ErrNotFound)
+	}
+	return &svc, nil
 }
 
 func (r *ServiceRepository) ListServices(ctx context.Context) ([]*service.Service, error) {
 	var services []*service.Service
-	err := r.db.Find(&services)
+	err := conn(ctx, r.db).Find(&services)
 	return services, err
 }
\ No newline at end of file
@@ -0,0 +1,33 @@
+package repositories
+
+import "context"
+
+type txKey struct{}
+
+// TransactionManager abre transações compartilhadas pelos repositórios através do context
+type TransactionManager struct {
+	db DBClient
+}
+
+func NewTransactionManager(db DBClient) *TransactionManager {
+	return &TransactionManager{db: db}
+}
+
+// WithinTransaction executa fn em uma transação. Chamadas aninhadas reutilizam a transação externa.
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(DBClient); ok {
+		return fn(ctx)
+	}
+
+	return m.db.Transaction(func(tx DBClient) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn retorna a transação aberta em ctx ou, fora dela, o cliente padrão
+func conn(ctx context.Context, db DBClient) DBClient {
+	if tx, ok := ctx.Value(txKey{}).(DBClient); ok {
+		return tx
+	}
+	return db
+}
@@ -15,19 +15,37 @@ func NewUserRepository(db DBClient) *UserRepository {
 }
 
 func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
-	return r.db.Create(u)
+	return conn(ctx, r.db).Create(u)
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	return conn(ctx, r.db).Save(u)
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Delete(&user.User{}, "id = ?", id)
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
 	var u user.User
-	err := r.db.First(&u, "id = ?", id)
-	return &u, err
+	if err := conn(ctx, r.db).First(&u, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, user.ErrNotFound)
+	}
+	return &u, nil
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	var u user.User
-	err := r.db.First(&u, "email = ?", email)
-	return &u, err
+	if err := conn(ctx, r.db).First(&u, "email = ?", email); err != nil {
+		return nil, translateNotFound(err, user.ErrNotFound)
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var users []*user.User
+	err := conn(ctx, r.db).Find(&users, "id IN ?", ids)
+	return users, err
 }
 
 type CompanyRepository struct {
@@ -39,19 +57,43 @@ func NewCompanyRepository(db DBClient) *CompanyRepository {
 }
 
 func (r *CompanyRepository) CreateCompany(ctx context.Context, company *user.Company) error {
-	return r.db.Create(company)
+	return conn(ctx, r.db).Create(company)
 }
 
 func (r *CompanyRepository) GetCompanyByID(ctx context.Context, id uuid.UUID) (*user.Company, error) {
 	var company user.Company
-	err := r.db.First(&company, "id = ?", id)
-	return &company, err
+	if err := conn(ctx, r.db).First(&company, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, user.ErrCompanyNotFound)
+	}
+	return &company, nil
 }
 
 func (r *CompanyRepository) GetCompanyByUserID(ctx context.Context, userID uuid.UUID) (*user.Company, error) {
 	var company user.Company
-	err := r.db.First(&company, "user_id = ?", userID)
-	return &company, err
+	if err := conn(ctx, r.db).First(&company, "user_id = ?", userID); err != nil {
+		return nil, translateNotFound(err, user.ErrCompanyNotFound)
+	}
+	return &company, nil
+}
+
+func (r *CompanyRepository) SetRequireProfessionalMFA(ctx context.Context, companyID uuid.UUID, required bool) error {
+	_, err := conn(ctx, r.db).Where("id = ?", companyID).Updates(&user.Company{}, map[string]interface{}{
+		"require_professional_mfa": required,
+	})
+	return err
+}
+
+func (r *CompanyRepository) SetTimeZone(ctx context.Context, companyID uuid.UUID, timeZone string) error {
+	rows, err := conn(ctx, r.db).Where("id = ?", companyID).Updates(&user.Company{}, map[string]interface{}{
+		"time_zone": timeZone,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return user.ErrCompanyNotFound
+	}
+	return nil
 }
 
 type ProfessionalRepository struct {
@@ -63,23 +105,46 @@ func NewProfessionalRepository(db DBClient) *ProfessionalRepository {
 }
 
 func (r *ProfessionalRepository) CreateProfessional(ctx context.Context, professional *user.Professional) error {
-	return r.db.Create(professional)
+	return conn(ctx, r.db).Create(professional)
 }
 
 func (r *ProfessionalRepository) GetProfessionalByID(ctx context.Context, id uuid.UUID) (*user.Professional, error) {
 	var professional user.Professional
-	err := r.db.First(&professional, "id = ?", id)
-	return &professional, err
+	if err := conn(ctx, r.db).First(&professional, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, user.ErrProfessionalNotFound)
+	}
+	return &professional, nil
 }
 
 func (r *ProfessionalRepository) GetProfessionalByUserID(ctx context.Context, userID uuid.UUID) (*user.Professional, error) {
 	var professional user.Professional
-	err := r.db.First(&professional, "user_id = ?", userID)
-	return &professional, err
+	if err := conn(ctx, r.db).First(&professional, "user_id = ?", userID); err != nil {
+		return nil, translateNotFound(err, user.ErrProfessionalNotFound)
+	}
+	return &professional, nil
 }
 
 func (r *ProfessionalRepository) ListByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*user.Professional, error) {
 	var professionals []*user.Professional
-	err := r.db.Find(&professionals, "company_id = ?", companyID)
+	err := conn(ctx, r.db).Find(&professionals, "company_id = ?", companyID)
+	return professionals, err
+}
+
+func (r *ProfessionalRepository) ListProfessionalsByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.Professional, error) {
+	var professionals []*user.Professional
+	err := conn(ctx, r.db).Preload("Company").Find(&professionals, "id IN ?", ids)
 	return professionals, err
-}
\ No newline at end of file
+}
+
+func (r *ProfessionalRepository) SetTimeZone(ctx context.Context, professionalID uuid.UUID, timeZone string) error {
+	rows, err := conn(ctx, r.db).Where("id = ?", professionalID).Updates(&user.Professional{}, map[string]interface{}{
+		"time_zone": timeZone,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return user.ErrProfessionalNotFound
+	}
+	return nil
+}
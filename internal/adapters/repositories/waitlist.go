@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+
+	"github.com/google/uuid"
+)
+
+type HoldRepository struct {
+	db DBClient
+}
+
+func NewHoldRepository(db DBClient) *HoldRepository {
+	return &HoldRepository{db: db}
+}
+
+func (r *HoldRepository) CreateHold(ctx context.Context, hold *appointment.Hold) error {
+	return conn(ctx, r.db).Create(hold)
+}
+
+func (r *HoldRepository) GetHoldByID(ctx context.Context, id uuid.UUID) (*appointment.Hold, error) {
+	var hold appointment.Hold
+	if err := conn(ctx, r.db).First(&hold, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrHoldNotFound)
+	}
+	return &hold, nil
+}
+
+func (r *HoldRepository) DeleteHold(ctx context.Context, id uuid.UUID) error {
+	return conn(ctx, r.db).Delete(&appointment.Hold{}, "id = ?", id)
+}
+
+func (r *HoldRepository) ExpireHold(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := conn(ctx, r.db).Where("id = ? AND expires_at > ?", id, at.UTC()).Updates(&appointment.Hold{}, map[string]interface{}{
+		"expires_at": at.UTC(),
+	})
+	return err
+}
+
+func (r *HoldRepository) ListActiveHolds(ctx context.Context, professionalIDs []uuid.UUID, from, to, now time.Time) ([]*appointment.Hold, error) {
+	var holds []*appointment.Hold
+	err := conn(ctx, r.db).
+		Where("professional_id IN ? AND expires_at > ?", professionalIDs, now.UTC()).
+		Where("start_time < ? AND end_time > ?", to.UTC(), from.UTC()).
+		Order("start_time").
+		Find(&holds)
+	return holds, err
+}
+
+func (r *HoldRepository) ListClientHolds(ctx context.Context, clientID uuid.UUID, now time.Time) ([]*appointment.Hold, error) {
+	var holds []*appointment.Hold
+	err := conn(ctx, r.db).
+		Where("client_id = ? AND expires_at > ? AND waitlist_entry_id IS NULL", clientID, now.UTC()).
+		Order("start_time").
+		Find(&holds)
+	return holds, err
+}
+
+func (r *HoldRepository) ListExpiredHolds(ctx context.Context, now time.Time) ([]*appointment.Hold, error) {
+	var holds []*appointment.Hold
+	err := conn(ctx, r.db).Where("expires_at <= ?", now.UTC()).Order("expires_at").Find(&holds)
+	return holds, err
+}
+
+type WaitlistRepository struct {
+	db DBClient
+}
+
+func NewWaitlistRepository(db DBClient) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+func (r *WaitlistRepository) CreateEntry(ctx context.Context, entry *appointment.WaitlistEntry) error {
+	return conn(ctx, r.db).Create(entry)
+}
+
+func (r *WaitlistRepository) GetEntryByID(ctx context.Context, id uuid.UUID) (*appointment.WaitlistEntry, error) {
+	var entry appointment.WaitlistEntry
+	if err := conn(ctx, r.db).First(&entry, "id = ?", id); err != nil {
+		return nil, translateNotFound(err, appointment.ErrWaitlistNotFound)
+	}
+	return &entry, nil
+}
+
+func (r *WaitlistRepository) UpdateEntry(ctx context.Context, entry *appointment.WaitlistEntry) error {
+	_, err := conn(ctx, r.db).Where("id = ?", entry.ID).Updates(&appointment.WaitlistEntry{}, map[string]interface{}{
+		"status":         entry.Status,
+		"hold_id":        entry.HoldID,
+		"appointment_id": entry.AppointmentID,
+	})
+	return err
+}
+
+func (r *WaitlistRepository) ListByClient(ctx context.Context, clientID uuid.UUID) ([]*appointment.WaitlistEntry, error) {
+	var entries []*appointment.WaitlistEntry
+	err := conn(ctx, r.db).Order("created_at").Find(&entries, "client_id = ?", clientID)
+	return entries, err
+}
+
+func (r *WaitlistRepository) ListByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*appointment.WaitlistEntry, error) {
+	var entries []*appointment.WaitlistEntry
+	err := conn(ctx, r.db).Order("created_at").Find(&entries, "professional_id = ?", professionalID)
+	return entries, err
+}
+
+func (r *WaitlistRepository) ListWaiting(ctx context.Context, professionalID, serviceID uuid.UUID, start, end time.Time) ([]*appointment.WaitlistEntry, error) {
+	var entries []*appointment.WaitlistEntry
+	err := conn(ctx, r.db).
+		Where("professional_id = ? AND service_id = ? AND status = ?", professionalID, serviceID, appointment.WaitlistWaiting).
+		Where("from_time <= ? AND to_time >= ?", start.UTC(), end.UTC()).
+		Order("created_at").
+		Find(&entries)
+	return entries, err
+}
+
+func (r *WaitlistRepository) HasOpenEntry(ctx context.Context, clientID, professionalID, serviceID uuid.UUID, from, to time.Time) (bool, error) {
+	var entries []*appointment.WaitlistEntry
+	err := conn(ctx, r.db).
+		Where("client_id = ? AND professional_id = ? AND service_id = ?", clientID, professionalID, serviceID).
+		Where("status IN ?", []string{appointment.WaitlistWaiting, appointment.WaitlistOffered}).
+		Where("from_time < ? AND to_time > ?", to.UTC(), from.UTC()).
+		Limit(1).
+		Find(&entries)
+	return len(entries) > 0, err
+}
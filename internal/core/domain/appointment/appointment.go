@@ -1,26 +1,87 @@
 package appointment
 
 import (
-	"time"
+	"errors"
 	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("agendamento não encontrado")
+	ErrConflict         = errors.New("o profissional já tem um agendamento nesse horário")
+	ErrInvalidStartTime = errors.New("start_time deve estar no formato RFC3339 e no futuro")
+	ErrInvalidRange     = errors.New("intervalo de busca inválido")
+	ErrSessionFull      = errors.New("não há mais vagas neste horário")
+	ErrAlreadyAttending = errors.New("o cliente já tem uma vaga neste horário")
 )
 
+// Status gravados antes do ciclo de vida explícito (ver lifecycle.go)
+const (
+	StatusScheduled = "scheduled"
+	StatusCancelled = "cancelled"
+)
+
+// FreeingStatuses são os status que não ocupam mais o horário do profissional
+var FreeingStatuses = []string{StatusCancelledByClient, StatusCancelledByProvider, StatusCancelled}
+
 type Appointment struct {
-	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
-	ClientID       uuid.UUID `json:"client_id" gorm:"type:uuid;not null"`
-	ProfessionalID uuid.UUID `json:"professional_id" gorm:"type:uuid;not null"`
-	ServiceID      uuid.UUID `json:"service_id" gorm:"type:uuid;not null"`
-	StartTime      time.Time `json:"start_time" gorm:"not null"`
-	EndTime        time.Time `json:"end_time" gorm:"not null"`
-	Status         string    `json:"status" gorm:"not null;default:'scheduled'"`
-	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ID              uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid"`
+	ClientID        uuid.UUID      `json:"client_id" gorm:"type:uuid;not null"`
+	ProfessionalID  uuid.UUID      `json:"professional_id" gorm:"type:uuid;not null;index:idx_appointments_professional_time"`
+	ServiceID       uuid.UUID      `json:"service_id" gorm:"type:uuid;not null"`
+	SeriesID        *uuid.UUID     `json:"series_id,omitempty" gorm:"type:uuid;index"`
+	StartTime       time.Time      `json:"start_time" gorm:"not null;index:idx_appointments_professional_time"`
+	EndTime         time.Time      `json:"end_time" gorm:"not null"`
+	Status          string         `json:"status" gorm:"not null;default:'pending'"`
+	StatusChangedAt *time.Time     `json:"status_changed_at,omitempty"`
+	StatusHistory   []StatusChange `json:"status_history,omitempty" gorm:"foreignKey:AppointmentID"`
+	RescheduleCount int            `json:"reschedule_count" gorm:"not null;default:0"`
+	// RescheduleHistory guarda os horários e profissionais anteriores, da remarcação mais antiga à mais recente
+	RescheduleHistory []Reschedule `json:"reschedule_history,omitempty" gorm:"foreignKey:AppointmentID"`
+	// LateCancellation marca cancelamentos do cliente feitos dentro da antecedência mínima da política
+	LateCancellation bool      `json:"late_cancellation,omitempty" gorm:"not null;default:false"`
+	CancellationFee  float64   `json:"cancellation_fee,omitempty" gorm:"not null;default:0"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
-type Availability struct {
-	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
-	ProfessionalID uuid.UUID `json:"professional_id" gorm:"type:uuid;not null"`
-	DayOfWeek      string    `json:"day_of_week" gorm:"not null"`
-	StartTime      string    `json:"start_time" gorm:"not null"`
-	EndTime        string    `json:"end_time" gorm:"not null"`
-	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
-}
\ No newline at end of file
+// Localize converte os horários para o fuso em que serão exibidos
+func (a *Appointment) Localize(loc *time.Location) {
+	a.StartTime = a.StartTime.In(loc)
+	a.EndTime = a.EndTime.In(loc)
+	a.CreatedAt = a.CreatedAt.In(loc)
+	if a.StatusChangedAt != nil {
+		changedAt := a.StatusChangedAt.In(loc)
+		a.StatusChangedAt = &changedAt
+	}
+	for i := range a.StatusHistory {
+		a.StatusHistory[i].Localize(loc)
+	}
+	for i := range a.RescheduleHistory {
+		a.RescheduleHistory[i].Localize(loc)
+	}
+}
+
+// Frees indica se o agendamento deixou de ocupar o horário do profissional
+func (a *Appointment) Frees() bool {
+	for _, status := range FreeingStatuses {
+		if a.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Slot é um horário livre para iniciar o serviço com o profissional
+type Slot struct {
+	ProfessionalID uuid.UUID `json:"professional_id"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	// SeatsAvailable é preenchido apenas em serviços em grupo
+	SeatsAvailable int `json:"seats_available,omitempty"`
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (s *Slot) Localize(loc *time.Location) {
+	s.StartTime = s.StartTime.In(loc)
+	s.EndTime = s.EndTime.In(loc)
+}
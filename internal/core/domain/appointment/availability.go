@@ -0,0 +1,184 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOutsideAvailability é retornado embrulhado com o motivo da recusa
+	ErrOutsideAvailability = errors.New("horário fora da disponibilidade do profissional")
+
+	// ErrInvalidAvailability é retornado embrulhado com o campo inválido
+	ErrInvalidAvailability  = errors.New("disponibilidade inválida")
+	ErrAvailabilityOverlap  = errors.New("a janela se sobrepõe a outra disponibilidade do mesmo dia")
+	ErrAvailabilityNotFound = errors.New("disponibilidade não encontrada")
+)
+
+const clockLayout = "15:04"
+
+// DayOfWeek é o dia da semana de uma disponibilidade, gravado pelo nome em inglês
+type DayOfWeek string
+
+const (
+	Sunday    DayOfWeek = "sunday"
+	Monday    DayOfWeek = "monday"
+	Tuesday   DayOfWeek = "tuesday"
+	Wednesday DayOfWeek = "wednesday"
+	Thursday  DayOfWeek = "thursday"
+	Friday    DayOfWeek = "friday"
+	Saturday  DayOfWeek = "saturday"
+)
+
+// Weekday converte o dia para time.Weekday, aceitando maiúsculas gravadas antes da validação
+func (d DayOfWeek) Weekday() (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(string(d), day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("dia da semana inválido: %q", string(d))
+}
+
+var weekdayNames = [...]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}
+
+// WeekdayName retorna o nome do dia da semana em português
+func WeekdayName(day time.Weekday) string {
+	return weekdayNames[day]
+}
+
+// Availability é uma janela semanal de atendimento do profissional
+type Availability struct {
+	ID             uuid.UUID           `json:"id" gorm:"primaryKey;type:uuid"`
+	ProfessionalID uuid.UUID           `json:"professional_id" gorm:"type:uuid;not null;index"`
+	DayOfWeek      DayOfWeek           `json:"day_of_week" gorm:"not null"`
+	StartTime      string              `json:"start_time" gorm:"not null"`
+	EndTime        string              `json:"end_time" gorm:"not null"`
+	Breaks         []AvailabilityBreak `json:"breaks" gorm:"foreignKey:AvailabilityID"`
+	CreatedAt      time.Time           `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AvailabilityBreak é uma pausa recorrente (ex.: almoço) dentro de uma janela de atendimento
+type AvailabilityBreak struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	AvailabilityID uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	StartTime      string    `json:"start_time" gorm:"not null"`
+	EndTime        string    `json:"end_time" gorm:"not null"`
+}
+
+// Weekday interpreta DayOfWeek
+func (a *Availability) Weekday() (time.Weekday, error) {
+	return a.DayOfWeek.Weekday()
+}
+
+// Window retorna o intervalo de atendimento no dia informado, interpretando StartTime e EndTime (HH:MM) no fuso de day
+func (a *Availability) Window(day time.Time) (time.Time, time.Time, error) {
+	return clockRange(day, a.StartTime, a.EndTime)
+}
+
+// Window retorna o intervalo da pausa no dia informado
+func (b *AvailabilityBreak) Window(day time.Time) (time.Time, time.Time, error) {
+	return clockRange(day, b.StartTime, b.EndTime)
+}
+
+// Validate normaliza dia e horários e verifica que a janela e suas pausas são coerentes
+func (a *Availability) Validate() error {
+	a.DayOfWeek = DayOfWeek(strings.ToLower(string(a.DayOfWeek)))
+	if _, err := a.DayOfWeek.Weekday(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAvailability, err)
+	}
+
+	var err error
+	if a.StartTime, a.EndTime, err = normalizeRange(a.StartTime, a.EndTime); err != nil {
+		return err
+	}
+
+	for i := range a.Breaks {
+		b := &a.Breaks[i]
+		if b.StartTime, b.EndTime, err = normalizeRange(b.StartTime, b.EndTime); err != nil {
+			return err
+		}
+		if b.StartTime <= a.StartTime || b.EndTime >= a.EndTime {
+			return fmt.Errorf("%w: a pausa %s-%s deve ficar dentro da janela %s-%s", ErrInvalidAvailability, b.StartTime, b.EndTime, a.StartTime, a.EndTime)
+		}
+		for _, other := range a.Breaks[:i] {
+			if b.StartTime < other.EndTime && other.StartTime < b.EndTime {
+				return fmt.Errorf("%w: as pausas %s-%s e %s-%s se sobrepõem", ErrInvalidAvailability, other.StartTime, other.EndTime, b.StartTime, b.EndTime)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Overlaps indica se as janelas caem no mesmo dia e se sobrepõem. Ambas devem estar validadas.
+func (a *Availability) Overlaps(other *Availability) bool {
+	return a.DayOfWeek == other.DayOfWeek && a.StartTime < other.EndTime && other.StartTime < a.EndTime
+}
+
+// normalizeRange converte os horários para HH:MM e exige início antes do fim
+func normalizeRange(start, end string) (string, string, error) {
+	startClock, err := time.Parse(clockLayout, start)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: horário %q não está no formato HH:MM", ErrInvalidAvailability, start)
+	}
+	endClock, err := time.Parse(clockLayout, end)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: horário %q não está no formato HH:MM", ErrInvalidAvailability, end)
+	}
+	if !startClock.Before(endClock) {
+		return "", "", fmt.Errorf("%w: o início (%s) deve ser anterior ao fim (%s)", ErrInvalidAvailability, start, end)
+	}
+	return startClock.Format(clockLayout), endClock.Format(clockLayout), nil
+}
+
+func clockRange(day time.Time, start, end string) (time.Time, time.Time, error) {
+	startTime, err := clockOn(day, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endTime, err := clockOn(day, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startTime, endTime, nil
+}
+
+func clockOn(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse(clockLayout, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("horário inválido: %q", clock)
+	}
+	year, month, date := day.Date()
+	return LocalTime(year, month, date, t.Hour(), t.Minute(), day.Location()), nil
+}
+
+// LocalTime converte um horário de parede em instante no fuso loc, tratando as transições de horário de verão:
+// um horário repetido (quando o relógio volta) resolve para a primeira ocorrência e um horário
+// inexistente (quando o relógio adianta) é deslocado para frente pelo tamanho do salto, como 02:30 -> 03:30
+func LocalTime(year int, month time.Month, day, hour, min int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	// As transições ficam a mais de um dia de distância, então no máximo dois deslocamentos são possíveis
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var valid []time.Time
+	for _, offset := range []int{offsetBefore, offsetAfter} {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		y, m, d := t.Date()
+		if time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, time.UTC).Equal(wall) {
+			valid = append(valid, t)
+		}
+	}
+	switch {
+	case len(valid) == 2 && valid[1].Before(valid[0]):
+		return valid[1]
+	case len(valid) > 0:
+		return valid[0]
+	}
+	return wall.Add(-time.Duration(offsetBefore) * time.Second).In(loc)
+}
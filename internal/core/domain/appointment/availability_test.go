@@ -0,0 +1,56 @@
+package appointment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalTimeAcrossDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		month      time.Month
+		day, hour  int
+		minute     int
+		wantUTC    string
+		wantOffset int
+	}{
+		{name: "horário comum", month: time.January, day: 15, hour: 9, wantUTC: "2030-01-15T14:00:00Z", wantOffset: -5},
+		{name: "horário de verão", month: time.July, day: 15, hour: 9, wantUTC: "2030-07-15T13:00:00Z", wantOffset: -4},
+		// Em 10/03/2030 o relógio pula de 02:00 para 03:00
+		{name: "horário inexistente", month: time.March, day: 10, hour: 2, minute: 30, wantUTC: "2030-03-10T07:30:00Z", wantOffset: -4},
+		// Em 03/11/2030 o relógio volta de 02:00 para 01:00 e 01:30 acontece duas vezes
+		{name: "horário repetido", month: time.November, day: 3, hour: 1, minute: 30, wantUTC: "2030-11-03T05:30:00Z", wantOffset: -4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LocalTime(2030, tt.month, tt.day, tt.hour, tt.minute, newYork)
+			if utc := got.UTC().Format(time.RFC3339); utc != tt.wantUTC {
+				t.Fatalf("instante = %s, esperado %s", utc, tt.wantUTC)
+			}
+			if _, offset := got.Zone(); offset != tt.wantOffset*3600 {
+				t.Fatalf("deslocamento = %ds, esperado %dh", offset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestAvailabilityWindowFollowsDayLocation(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	a := &Availability{DayOfWeek: Monday, StartTime: "08:00", EndTime: "18:00"}
+
+	start, end, err := a.Window(time.Date(2030, time.January, 14, 0, 0, 0, 0, saoPaulo))
+	if err != nil {
+		t.Fatalf("Window: %v", err)
+	}
+	if start.UTC().Hour() != 11 || end.UTC().Hour() != 21 {
+		t.Fatalf("janela = %s-%s, esperado 11:00-21:00 UTC", start.UTC(), end.UTC())
+	}
+}
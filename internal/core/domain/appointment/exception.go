@@ -0,0 +1,97 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidException é retornado embrulhado com o campo inválido
+	ErrInvalidException     = errors.New("exceção de disponibilidade inválida")
+	ErrExceptionNotFound    = errors.New("exceção de disponibilidade não encontrada")
+	ErrInvalidHoliday       = errors.New("feriado inválido")
+	ErrHolidayNotFound      = errors.New("feriado não encontrado")
+	ErrHolidayAlreadyExists = errors.New("a empresa já tem um feriado nesta data")
+)
+
+const dateLayout = "2006-01-02"
+
+// ExceptionKind diferencia bloqueios de horários extras em datas específicas
+type ExceptionKind string
+
+const (
+	// ExceptionTimeOff bloqueia o período (férias, atestado, compromisso)
+	ExceptionTimeOff ExceptionKind = "time_off"
+	// ExceptionExtraHours abre o período para atendimento fora das janelas semanais
+	ExceptionExtraHours ExceptionKind = "extra_hours"
+)
+
+// AvailabilityException altera a agenda semanal de um profissional em um período específico
+type AvailabilityException struct {
+	ID             uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid"`
+	ProfessionalID uuid.UUID     `json:"professional_id" gorm:"type:uuid;not null;index"`
+	Kind           ExceptionKind `json:"kind" gorm:"not null"`
+	StartTime      time.Time     `json:"start_time" gorm:"not null"`
+	EndTime        time.Time     `json:"end_time" gorm:"not null"`
+	Reason         string        `json:"reason,omitempty"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Validate verifica o tipo e normaliza o período para UTC
+func (e *AvailabilityException) Validate() error {
+	if e.Kind != ExceptionTimeOff && e.Kind != ExceptionExtraHours {
+		return fmt.Errorf("%w: kind deve ser %q ou %q", ErrInvalidException, ExceptionTimeOff, ExceptionExtraHours)
+	}
+	if !e.StartTime.Before(e.EndTime) {
+		return fmt.Errorf("%w: start_time deve ser anterior a end_time", ErrInvalidException)
+	}
+	e.StartTime, e.EndTime = e.StartTime.UTC(), e.EndTime.UTC()
+	return nil
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (e *AvailabilityException) Localize(loc *time.Location) {
+	e.StartTime = e.StartTime.In(loc)
+	e.EndTime = e.EndTime.In(loc)
+	e.CreatedAt = e.CreatedAt.In(loc)
+}
+
+// Holiday é um dia sem atendimento para todos os profissionais da empresa
+type Holiday struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	CompanyID uuid.UUID `json:"company_id" gorm:"type:uuid;not null;uniqueIndex:idx_holidays_company_date"`
+	Date      string    `json:"date" gorm:"not null;uniqueIndex:idx_holidays_company_date"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Validate exige a data no formato AAAA-MM-DD e um nome
+func (h *Holiday) Validate() error {
+	if _, err := time.Parse(dateLayout, h.Date); err != nil {
+		return fmt.Errorf("%w: date %q não está no formato AAAA-MM-DD", ErrInvalidHoliday, h.Date)
+	}
+	h.Name = strings.TrimSpace(h.Name)
+	if h.Name == "" {
+		return fmt.Errorf("%w: name é obrigatório", ErrInvalidHoliday)
+	}
+	return nil
+}
+
+// Range retorna o dia inteiro do feriado no fuso informado, com 23 ou 25 horas nos dias de mudança de horário
+func (h *Holiday) Range(loc *time.Location) (time.Time, time.Time, error) {
+	day, err := time.Parse(dateLayout, h.Date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	year, month, date := day.Date()
+	return LocalTime(year, month, date, 0, 0, loc), LocalTime(year, month, date+1, 0, 0, loc), nil
+}
+
+// DateOf formata o dia de t usado nas consultas de feriados
+func DateOf(t time.Time) string {
+	return t.Format(dateLayout)
+}
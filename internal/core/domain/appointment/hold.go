@@ -0,0 +1,46 @@
+package appointment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrHoldNotFound = errors.New("reserva temporária não encontrada")
+	ErrHoldExpired  = errors.New("a reserva temporária expirou")
+	ErrSlotHeld     = errors.New("o horário está reservado temporariamente para outro cliente")
+	ErrHoldMismatch = errors.New("a reserva temporária é de outro serviço, profissional ou horário")
+	// ErrHoldOutOfRange indica um horário já passado ou além do período em que a busca de horários alcança
+	ErrHoldOutOfRange   = errors.New("o horário da reserva deve estar no futuro e dentro do período de busca de horários")
+	ErrHoldLimitReached = errors.New("o cliente atingiu o limite de reservas temporárias ativas")
+)
+
+// Hold reserva um horário do profissional para um cliente até ExpiresAt. Enquanto válida, bloqueia
+// o horário (ou uma vaga da sessão, em serviços em grupo) para os demais clientes.
+type Hold struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	ProfessionalID uuid.UUID `json:"professional_id" gorm:"type:uuid;not null;index"`
+	ServiceID      uuid.UUID `json:"service_id" gorm:"type:uuid;not null"`
+	ClientID       uuid.UUID `json:"client_id" gorm:"type:uuid;not null"`
+	StartTime      time.Time `json:"start_time" gorm:"not null"`
+	EndTime        time.Time `json:"end_time" gorm:"not null"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null;index"`
+	// WaitlistEntryID indica a entrada da lista de espera à qual o horário foi oferecido
+	WaitlistEntryID *uuid.UUID `json:"waitlist_entry_id,omitempty" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Expired indica se a reserva já não vale em now
+func (h *Hold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (h *Hold) Localize(loc *time.Location) {
+	h.StartTime = h.StartTime.In(loc)
+	h.EndTime = h.EndTime.In(loc)
+	h.ExpiresAt = h.ExpiresAt.In(loc)
+	h.CreatedAt = h.CreatedAt.In(loc)
+}
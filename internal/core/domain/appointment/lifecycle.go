@@ -0,0 +1,119 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTransition é retornado embrulhado com o status atual do agendamento
+var ErrInvalidTransition = errors.New("transição de status inválida")
+
+// Ciclo de vida: pending -> confirmed -> checked_in -> completed, com cancelamentos e falta
+const (
+	StatusPending             = "pending"
+	StatusConfirmed           = "confirmed"
+	StatusCheckedIn           = "checked_in"
+	StatusCompleted           = "completed"
+	StatusCancelledByClient   = "cancelled_by_client"
+	StatusCancelledByProvider = "cancelled_by_provider"
+	StatusNoShow              = "no_show"
+)
+
+var statusNames = map[string]string{
+	StatusPending:             "pendente",
+	StatusConfirmed:           "confirmado",
+	StatusCheckedIn:           "em atendimento",
+	StatusCompleted:           "concluído",
+	StatusCancelledByClient:   "cancelado pelo cliente",
+	StatusCancelledByProvider: "cancelado pelo profissional",
+	StatusNoShow:              "marcado como falta",
+	StatusScheduled:           "agendado",
+	StatusCancelled:           "cancelado",
+}
+
+// Transition é uma mudança de status permitida a partir de um conjunto de status
+type Transition struct {
+	// Action descreve a ação nas mensagens de erro
+	Action string
+	From   []string
+	To     string
+}
+
+var (
+	Confirm          = Transition{Action: "confirmar", From: []string{StatusPending}, To: StatusConfirmed}
+	CheckIn          = Transition{Action: "registrar a chegada de", From: []string{StatusConfirmed}, To: StatusCheckedIn}
+	Complete         = Transition{Action: "concluir", From: []string{StatusCheckedIn}, To: StatusCompleted}
+	CancelByClient   = Transition{Action: "cancelar", From: []string{StatusPending, StatusConfirmed}, To: StatusCancelledByClient}
+	CancelByProvider = Transition{Action: "cancelar", From: []string{StatusPending, StatusConfirmed}, To: StatusCancelledByProvider}
+	MarkNoShow       = Transition{Action: "registrar falta em", From: []string{StatusConfirmed}, To: StatusNoShow}
+)
+
+// Actor identifica quem altera o agendamento. O profissional atribuído e a empresa dele (Provider)
+// não estão sujeitos à política de cancelamento e remarcação.
+type Actor struct {
+	UserID   uuid.UUID
+	Provider bool
+}
+
+// StatusChange registra cada transição do agendamento, com quem a fez e o motivo informado
+type StatusChange struct {
+	ID            uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	AppointmentID uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status" gorm:"not null"`
+	Reason        string    `json:"reason,omitempty"`
+	ChangedBy     uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CurrentStatus retorna o status no ciclo de vida atual; agendamentos antigos "scheduled" contam como confirmados
+func (a *Appointment) CurrentStatus() string {
+	if a.Status == StatusScheduled {
+		return StatusConfirmed
+	}
+	return a.Status
+}
+
+// Apply valida a transição e retorna o registro correspondente, sem alterar o agendamento
+func (a *Appointment) Apply(t Transition, actorID uuid.UUID, reason string, at time.Time) (*StatusChange, error) {
+	current := a.CurrentStatus()
+	allowed := false
+	for _, from := range t.From {
+		if current == from {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: não é possível %s um agendamento %s", ErrInvalidTransition, t.Action, StatusName(a.Status))
+	}
+	if t.To == StatusNoShow && at.Before(a.StartTime) {
+		return nil, fmt.Errorf("%w: a falta só pode ser registrada depois do início do agendamento", ErrInvalidTransition)
+	}
+
+	return &StatusChange{
+		ID:            uuid.New(),
+		AppointmentID: a.ID,
+		FromStatus:    a.Status,
+		ToStatus:      t.To,
+		Reason:        reason,
+		ChangedBy:     actorID,
+		CreatedAt:     at,
+	}, nil
+}
+
+// StatusName retorna o nome do status em português
+func StatusName(status string) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return status
+}
+
+// Localize converte o horário da transição para o fuso em que será exibido
+func (c *StatusChange) Localize(loc *time.Location) {
+	c.CreatedAt = c.CreatedAt.In(loc)
+}
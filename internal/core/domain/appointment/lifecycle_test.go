@@ -0,0 +1,54 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyTransitions(t *testing.T) {
+	start := time.Date(2030, time.January, 14, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     string
+		transition Transition
+		at         time.Time
+		want       error
+	}{
+		{name: "confirmar pendente", status: StatusPending, transition: Confirm, at: start.Add(-time.Hour)},
+		{name: "chegada de confirmado", status: StatusConfirmed, transition: CheckIn, at: start},
+		{name: "chegada de agendado antigo", status: StatusScheduled, transition: CheckIn, at: start},
+		{name: "concluir em atendimento", status: StatusCheckedIn, transition: Complete, at: start.Add(time.Hour)},
+		{name: "cliente cancela pendente", status: StatusPending, transition: CancelByClient, at: start.Add(-time.Hour)},
+		{name: "profissional cancela confirmado", status: StatusConfirmed, transition: CancelByProvider, at: start.Add(-time.Hour)},
+		{name: "falta depois do início", status: StatusConfirmed, transition: MarkNoShow, at: start.Add(time.Minute)},
+		{name: "falta antes do início", status: StatusConfirmed, transition: MarkNoShow, at: start.Add(-time.Minute), want: ErrInvalidTransition},
+		{name: "concluir sem chegada", status: StatusConfirmed, transition: Complete, at: start, want: ErrInvalidTransition},
+		{name: "confirmar duas vezes", status: StatusConfirmed, transition: Confirm, at: start, want: ErrInvalidTransition},
+		{name: "cancelar concluído", status: StatusCompleted, transition: CancelByClient, at: start, want: ErrInvalidTransition},
+		{name: "chegada de cancelado", status: StatusCancelledByProvider, transition: CheckIn, at: start, want: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Appointment{ID: uuid.New(), StartTime: start, EndTime: start.Add(time.Hour), Status: tt.status}
+			actorID := uuid.New()
+
+			change, err := a.Apply(tt.transition, actorID, "motivo", tt.at)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if change.FromStatus != tt.status || change.ToStatus != tt.transition.To || change.ChangedBy != actorID || change.AppointmentID != a.ID {
+				t.Fatalf("registro = %+v, esperado %s -> %s", change, tt.status, tt.transition.To)
+			}
+			if a.Status != tt.status {
+				t.Fatal("Apply não deveria alterar o agendamento")
+			}
+		})
+	}
+}
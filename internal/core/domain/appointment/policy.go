@@ -0,0 +1,91 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPolicyViolation é retornado embrulhado com a regra que impediu a operação
+	ErrPolicyViolation = errors.New("a política de cancelamento e remarcação não permite a operação")
+	// ErrInvalidPolicy é retornado embrulhado com o campo inválido
+	ErrInvalidPolicy          = errors.New("política inválida")
+	ErrPolicyNotFound         = errors.New("política não encontrada")
+	ErrPolicyManagedByCompany = errors.New("o profissional segue a política da empresa")
+)
+
+// Policy define as regras de cancelamento e remarcação de uma empresa ou de um profissional autônomo.
+// Profissionais vinculados a uma empresa seguem a política dela.
+type Policy struct {
+	ID      uuid.UUID `json:"-" gorm:"primaryKey;type:uuid"`
+	OwnerID uuid.UUID `json:"-" gorm:"type:uuid;not null;uniqueIndex"`
+	// MinNoticeMinutes é a antecedência mínima, em relação ao início, para o cliente cancelar ou remarcar
+	MinNoticeMinutes int `json:"min_notice_minutes" gorm:"not null;default:0"`
+	// MaxReschedules limita quantas vezes o cliente pode remarcar; nulo significa sem limite
+	MaxReschedules *int `json:"max_reschedules"`
+	// AllowLateCancellation aceita cancelamentos dentro da antecedência mínima, marcando-os como tardios
+	AllowLateCancellation bool      `json:"allow_late_cancellation" gorm:"not null;default:false"`
+	LateCancellationFee   float64   `json:"late_cancellation_fee" gorm:"not null;default:0"`
+	UpdatedAt             time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate exige valores não negativos e taxa apenas quando o cancelamento tardio é aceito
+func (p *Policy) Validate() error {
+	if p.MinNoticeMinutes < 0 {
+		return fmt.Errorf("%w: min_notice_minutes não pode ser negativo", ErrInvalidPolicy)
+	}
+	if p.MaxReschedules != nil && *p.MaxReschedules < 0 {
+		return fmt.Errorf("%w: max_reschedules não pode ser negativo", ErrInvalidPolicy)
+	}
+	if p.LateCancellationFee < 0 {
+		return fmt.Errorf("%w: late_cancellation_fee não pode ser negativo", ErrInvalidPolicy)
+	}
+	if p.LateCancellationFee > 0 && !p.AllowLateCancellation {
+		return fmt.Errorf("%w: late_cancellation_fee exige allow_late_cancellation", ErrInvalidPolicy)
+	}
+	return nil
+}
+
+// MinNotice retorna a antecedência mínima como duração
+func (p *Policy) MinNotice() time.Duration {
+	return time.Duration(p.MinNoticeMinutes) * time.Minute
+}
+
+// CheckCancellation indica se o cancelamento em now é tardio, ou o recusa quando a política não aceita atrasos
+func (p *Policy) CheckCancellation(a *Appointment, now time.Time) (bool, error) {
+	if !p.withinNotice(a, now) {
+		return false, nil
+	}
+	if !p.AllowLateCancellation {
+		return false, fmt.Errorf("%w: cancelamentos exigem %s de antecedência", ErrPolicyViolation, formatNotice(p.MinNotice()))
+	}
+	return true, nil
+}
+
+// CheckReschedule exige a antecedência mínima e que o limite de remarcações não tenha sido atingido
+func (p *Policy) CheckReschedule(a *Appointment, now time.Time) error {
+	if p.withinNotice(a, now) {
+		return fmt.Errorf("%w: remarcações exigem %s de antecedência", ErrPolicyViolation, formatNotice(p.MinNotice()))
+	}
+	if p.MaxReschedules != nil && a.RescheduleCount >= *p.MaxReschedules {
+		return fmt.Errorf("%w: o agendamento já foi remarcado %d vez(es), o máximo permitido", ErrPolicyViolation, a.RescheduleCount)
+	}
+	return nil
+}
+
+func (p *Policy) withinNotice(a *Appointment, now time.Time) bool {
+	return a.StartTime.Sub(now) < p.MinNotice()
+}
+
+func formatNotice(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d dia(s)", int(d/(24*time.Hour)))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hora(s)", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d minuto(s)", int(d/time.Minute))
+}
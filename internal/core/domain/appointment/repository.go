@@ -2,6 +2,7 @@ package appointment
 
 import (
 	"context"
+	"time"
 	"github.com/google/uuid"
 )
 
@@ -10,9 +11,93 @@ type Repository interface {
 	GetAppointmentByID(ctx context.Context, id uuid.UUID) (*Appointment, error)
 	ListAppointments(ctx context.Context, clientID uuid.UUID) ([]*Appointment, error)
 	ListByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*Appointment, error)
+	// ListByCompany retorna os agendamentos de todos os profissionais da empresa
+	ListByCompany(ctx context.Context, companyID uuid.UUID) ([]*Appointment, error)
+	// LockProfessionalSchedule serializa, até o fim da transação, as alterações na agenda do profissional
+	LockProfessionalSchedule(ctx context.Context, professionalID uuid.UUID) error
+	// HasConflict indica se algum agendamento ativo do profissional, exceto excludeID, se sobrepõe a [start, end)
+	HasConflict(ctx context.Context, professionalID uuid.UUID, start, end time.Time, excludeID uuid.UUID) (bool, error)
+	// ListActiveInRange retorna os agendamentos ativos dos profissionais que se sobrepõem a [from, to)
+	ListActiveInRange(ctx context.Context, professionalIDs []uuid.UUID, from, to time.Time) ([]*Appointment, error)
+	// ChangeStatus grava change.ToStatus se o agendamento ainda estiver em change.FromStatus, junto
+	// com o registro da transição. Retorna ErrInvalidTransition se o status mudou nesse meio-tempo.
+	ChangeStatus(ctx context.Context, change *StatusChange) error
+	AddStatusChange(ctx context.Context, change *StatusChange) error
+	RecordLateCancellation(ctx context.Context, id uuid.UUID, fee float64) error
+	// UpdateSchedule grava o novo horário, o profissional e o contador de remarcações
+	UpdateSchedule(ctx context.Context, appointment *Appointment) error
+	AddReschedule(ctx context.Context, reschedule *Reschedule) error
+
+	CreateSeries(ctx context.Context, series *Series) error
+	// GetSeriesByID retorna a série com todas as ocorrências, em ordem de início
+	GetSeriesByID(ctx context.Context, id uuid.UUID) (*Series, error)
+	ListBySeries(ctx context.Context, seriesID uuid.UUID) ([]*Appointment, error)
+	// SetSeriesProfessional grava o profissional que passa a atender a série
+	SetSeriesProfessional(ctx context.Context, seriesID, professionalID uuid.UUID) error
+}
+
+// HoldRepository guarda as reservas temporárias de horários
+type HoldRepository interface {
+	CreateHold(ctx context.Context, hold *Hold) error
+	GetHoldByID(ctx context.Context, id uuid.UUID) (*Hold, error)
+	DeleteHold(ctx context.Context, id uuid.UUID) error
+	// ExpireHold antecipa o vencimento da reserva para at
+	ExpireHold(ctx context.Context, id uuid.UUID, at time.Time) error
+	// ListActiveHolds retorna as reservas dos profissionais ainda válidas em now que se sobrepõem a [from, to)
+	ListActiveHolds(ctx context.Context, professionalIDs []uuid.UUID, from, to, now time.Time) ([]*Hold, error)
+	// ListClientHolds retorna as reservas do cliente ainda válidas em now, sem as oferecidas pela lista de espera
+	ListClientHolds(ctx context.Context, clientID uuid.UUID, now time.Time) ([]*Hold, error)
+	// ListExpiredHolds retorna as reservas vencidas até now, das mais antigas para as mais novas
+	ListExpiredHolds(ctx context.Context, now time.Time) ([]*Hold, error)
+}
+
+// WaitlistRepository guarda as entradas da lista de espera
+type WaitlistRepository interface {
+	CreateEntry(ctx context.Context, entry *WaitlistEntry) error
+	GetEntryByID(ctx context.Context, id uuid.UUID) (*WaitlistEntry, error)
+	// UpdateEntry grava o status, a reserva e o agendamento da entrada
+	UpdateEntry(ctx context.Context, entry *WaitlistEntry) error
+	ListByClient(ctx context.Context, clientID uuid.UUID) ([]*WaitlistEntry, error)
+	ListByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*WaitlistEntry, error)
+	// ListWaiting retorna, em ordem de chegada, as entradas aguardando que aceitam [start, end) do serviço com o profissional
+	ListWaiting(ctx context.Context, professionalID, serviceID uuid.UUID, start, end time.Time) ([]*WaitlistEntry, error)
+	// HasOpenEntry indica se o cliente já aguarda ou tem oferta no serviço com o profissional em período que se sobrepõe a [from, to)
+	HasOpenEntry(ctx context.Context, clientID, professionalID, serviceID uuid.UUID, from, to time.Time) (bool, error)
+}
+
+// PolicyRepository guarda as políticas por empresa ou profissional autônomo
+type PolicyRepository interface {
+	GetPolicy(ctx context.Context, ownerID uuid.UUID) (*Policy, error)
+	SavePolicy(ctx context.Context, policy *Policy) error
 }
 
 type AvailabilityRepository interface {
 	CreateAvailability(ctx context.Context, availability *Availability) error
+	GetAvailabilityByID(ctx context.Context, id uuid.UUID) (*Availability, error)
+	UpdateAvailability(ctx context.Context, availability *Availability) error
+	DeleteAvailability(ctx context.Context, id uuid.UUID) error
 	GetByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*Availability, error)
-}
\ No newline at end of file
+	ListByProfessionals(ctx context.Context, professionalIDs []uuid.UUID) ([]*Availability, error)
+}
+
+// ExceptionRepository guarda as exceções por profissional e os feriados das empresas
+type ExceptionRepository interface {
+	CreateException(ctx context.Context, exception *AvailabilityException) error
+	GetExceptionByID(ctx context.Context, id uuid.UUID) (*AvailabilityException, error)
+	DeleteException(ctx context.Context, id uuid.UUID) error
+	// ListExceptions retorna as exceções dos profissionais que se sobrepõem a [from, to)
+	ListExceptions(ctx context.Context, professionalIDs []uuid.UUID, from, to time.Time) ([]*AvailabilityException, error)
+
+	CreateHoliday(ctx context.Context, holiday *Holiday) error
+	GetHolidayByID(ctx context.Context, id uuid.UUID) (*Holiday, error)
+	DeleteHoliday(ctx context.Context, id uuid.UUID) error
+	ListCompanyHolidays(ctx context.Context, companyID uuid.UUID) ([]*Holiday, error)
+	// ListHolidays retorna os feriados das empresas entre as datas (AAAA-MM-DD), inclusive
+	ListHolidays(ctx context.Context, companyIDs []uuid.UUID, fromDate, toDate string) ([]*Holiday, error)
+}
+
+// TransactionManager executa fn em uma transação; os repositórios chamados com o
+// ctx recebido por fn participam dela
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
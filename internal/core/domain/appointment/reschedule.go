@@ -0,0 +1,34 @@
+package appointment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNothingToReschedule é retornado quando a remarcação não altera horário nem profissional
+var ErrNothingToReschedule = errors.New("informe um novo start_time ou professional_id")
+
+// Reschedule registra o horário e o profissional anteriores a cada remarcação
+type Reschedule struct {
+	ID                 uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	AppointmentID      uuid.UUID `json:"-" gorm:"type:uuid;not null;index"`
+	FromProfessionalID uuid.UUID `json:"from_professional_id" gorm:"type:uuid;not null"`
+	FromStartTime      time.Time `json:"from_start_time" gorm:"not null"`
+	FromEndTime        time.Time `json:"from_end_time" gorm:"not null"`
+	ToProfessionalID   uuid.UUID `json:"to_professional_id" gorm:"type:uuid;not null"`
+	ToStartTime        time.Time `json:"to_start_time" gorm:"not null"`
+	ToEndTime          time.Time `json:"to_end_time" gorm:"not null"`
+	ChangedBy          uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (r *Reschedule) Localize(loc *time.Location) {
+	r.FromStartTime = r.FromStartTime.In(loc)
+	r.FromEndTime = r.FromEndTime.In(loc)
+	r.ToStartTime = r.ToStartTime.In(loc)
+	r.ToEndTime = r.ToEndTime.In(loc)
+	r.CreatedAt = r.CreatedAt.In(loc)
+}
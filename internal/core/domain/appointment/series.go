@@ -0,0 +1,189 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidRecurrence é retornado embrulhado com o campo inválido
+	ErrInvalidRecurrence = errors.New("recorrência inválida")
+	ErrSeriesNotFound    = errors.New("série não encontrada")
+	// ErrSeriesConflict é embrulhado por SeriesConflictError com as ocorrências que não couberam
+	ErrSeriesConflict = errors.New("ocorrências da série não puderam ser agendadas")
+	ErrNotInSeries    = errors.New("o agendamento não pertence a uma série")
+	ErrInvalidScope   = errors.New("scope deve ser \"this\", \"following\" ou \"all\"")
+)
+
+// MaxSeriesOccurrences limita o tamanho de uma série, em número de ocorrências
+const MaxSeriesOccurrences = 52
+
+// Frequências aceitas na recorrência
+const (
+	FrequencyWeekly   = "weekly"
+	FrequencyBiweekly = "biweekly"
+)
+
+// Modos de reserva da série quando alguma ocorrência não cabe na agenda
+const (
+	// SeriesAllOrNothing não agenda nada se alguma ocorrência estiver em conflito
+	SeriesAllOrNothing = "all_or_nothing"
+	// SeriesBookAvailable agenda as ocorrências livres e informa as demais
+	SeriesBookAvailable = "book_available"
+)
+
+// Escopos de alterações em agendamentos de uma série
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+	ScopeAll       = "all"
+)
+
+// Recurrence é uma regra no estilo RRULE: semanal ou quinzenal, terminando após Count ocorrências
+// ou na data Until (AAAA-MM-DD, inclusive, no fuso do profissional)
+type Recurrence struct {
+	Frequency string  `json:"frequency" gorm:"not null"`
+	Count     *int    `json:"count,omitempty"`
+	Until     *string `json:"until,omitempty"`
+}
+
+// Series agrupa os agendamentos criados por uma recorrência
+type Series struct {
+	ID             uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	ClientID       uuid.UUID  `json:"client_id" gorm:"type:uuid;not null;index"`
+	ProfessionalID uuid.UUID  `json:"professional_id" gorm:"type:uuid;not null"`
+	ServiceID      uuid.UUID  `json:"service_id" gorm:"type:uuid;not null"`
+	Recurrence     Recurrence `json:"recurrence" gorm:"embedded;embeddedPrefix:recurrence_"`
+	// Appointments são as ocorrências agendadas, em ordem de início
+	Appointments []*Appointment `json:"appointments" gorm:"foreignKey:SeriesID"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// OccurrenceConflict explica por que uma ocorrência da série não pôde ser agendada
+type OccurrenceConflict struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Reason    string    `json:"reason"`
+}
+
+// SeriesBooking é o resultado da reserva de uma série
+type SeriesBooking struct {
+	Series    *Series              `json:"series"`
+	Conflicts []OccurrenceConflict `json:"conflicts"`
+}
+
+// SeriesConflictError lista as ocorrências em conflito quando nenhuma ocorrência foi agendada
+type SeriesConflictError struct {
+	Conflicts []OccurrenceConflict
+}
+
+func (e *SeriesConflictError) Error() string {
+	return fmt.Sprintf("%s: %d ocorrência(s) em conflito", ErrSeriesConflict, len(e.Conflicts))
+}
+
+func (e *SeriesConflictError) Unwrap() error {
+	return ErrSeriesConflict
+}
+
+// Occurrences valida a regra e retorna os inícios da série a partir de first, sempre no mesmo horário
+// local de loc, inclusive nas semanas de mudança de horário de verão
+func (r *Recurrence) Occurrences(first time.Time, loc *time.Location) ([]time.Time, error) {
+	step := 7
+	switch r.Frequency {
+	case FrequencyWeekly:
+	case FrequencyBiweekly:
+		step = 14
+	default:
+		return nil, fmt.Errorf("%w: frequency deve ser %q ou %q", ErrInvalidRecurrence, FrequencyWeekly, FrequencyBiweekly)
+	}
+	if (r.Count == nil) == (r.Until == nil) {
+		return nil, fmt.Errorf("%w: informe count ou until, apenas um deles", ErrInvalidRecurrence)
+	}
+
+	local := first.In(loc)
+	year, month, day := local.Date()
+	count := MaxSeriesOccurrences
+	last := ""
+	if r.Count != nil {
+		if *r.Count < 1 || *r.Count > MaxSeriesOccurrences {
+			return nil, fmt.Errorf("%w: count deve estar entre 1 e %d", ErrInvalidRecurrence, MaxSeriesOccurrences)
+		}
+		count = *r.Count
+	} else {
+		until, err := time.Parse(dateLayout, *r.Until)
+		if err != nil {
+			return nil, fmt.Errorf("%w: until %q não está no formato AAAA-MM-DD", ErrInvalidRecurrence, *r.Until)
+		}
+		last = DateOf(until)
+		if last < DateOf(local) {
+			return nil, fmt.Errorf("%w: until não pode ser anterior à primeira ocorrência", ErrInvalidRecurrence)
+		}
+	}
+
+	var starts []time.Time
+	for i := 0; ; i++ {
+		start := LocalTime(year, month, day+i*step, local.Hour(), local.Minute(), loc)
+		if last != "" && DateOf(start.In(loc)) > last {
+			break
+		}
+		if len(starts) == count {
+			if last != "" {
+				return nil, fmt.Errorf("%w: a série teria mais de %d ocorrências", ErrInvalidRecurrence, MaxSeriesOccurrences)
+			}
+			break
+		}
+		starts = append(starts, start.UTC())
+	}
+	return starts, nil
+}
+
+// SelectOccurrences retorna, em ordem de início, os agendamentos afetados por uma alteração em anchor:
+// apenas ele, ele e os seguintes da série ou toda a série. Ocorrências já atendidas, canceladas ou que
+// já começaram ficam de fora.
+func SelectOccurrences(anchor *Appointment, series []*Appointment, scope string, now time.Time) ([]*Appointment, error) {
+	var after time.Time
+	switch scope {
+	case ScopeThis:
+		return []*Appointment{anchor}, nil
+	case ScopeFollowing:
+		after = anchor.StartTime
+	case ScopeAll:
+		after = now
+	default:
+		return nil, ErrInvalidScope
+	}
+	if anchor.SeriesID == nil {
+		return nil, ErrNotInSeries
+	}
+
+	selected := []*Appointment{anchor}
+	for _, a := range series {
+		if a.ID == anchor.ID || !a.StartTime.After(after) {
+			continue
+		}
+		switch a.CurrentStatus() {
+		case StatusPending, StatusConfirmed:
+			selected = append(selected, a)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].StartTime.Before(selected[j].StartTime) })
+	return selected, nil
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (s *Series) Localize(loc *time.Location) {
+	s.CreatedAt = s.CreatedAt.In(loc)
+	for _, a := range s.Appointments {
+		a.Localize(loc)
+	}
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (c *OccurrenceConflict) Localize(loc *time.Location) {
+	c.StartTime = c.StartTime.In(loc)
+	c.EndTime = c.EndTime.In(loc)
+}
@@ -0,0 +1,117 @@
+package appointment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRecurrenceKeepsLocalTimeAcrossDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	count := 3
+	r := &Recurrence{Frequency: FrequencyWeekly, Count: &count}
+
+	// Em 10/03/2030 começa o horário de verão: a terceira ocorrência continua às 09:00 locais
+	starts, err := r.Occurrences(LocalTime(2030, time.March, 3, 9, 0, newYork), newYork)
+	if err != nil {
+		t.Fatalf("Occurrences: %v", err)
+	}
+	want := []string{"2030-03-03T14:00:00Z", "2030-03-10T13:00:00Z", "2030-03-17T13:00:00Z"}
+	if len(starts) != len(want) {
+		t.Fatalf("%d ocorrências, esperado %d", len(starts), len(want))
+	}
+	for i, start := range starts {
+		if got := start.Format(time.RFC3339); got != want[i] {
+			t.Fatalf("ocorrência %d = %s, esperado %s", i, got, want[i])
+		}
+	}
+}
+
+func TestRecurrenceUntilIsInclusive(t *testing.T) {
+	until := "2030-02-11"
+	r := &Recurrence{Frequency: FrequencyBiweekly, Until: &until}
+
+	starts, err := r.Occurrences(time.Date(2030, time.January, 14, 10, 0, 0, 0, time.UTC), time.UTC)
+	if err != nil {
+		t.Fatalf("Occurrences: %v", err)
+	}
+	if len(starts) != 3 || DateOf(starts[2]) != until {
+		t.Fatalf("ocorrências = %v, esperado três até %s", starts, until)
+	}
+}
+
+func TestRecurrenceValidation(t *testing.T) {
+	first := time.Date(2030, time.January, 14, 10, 0, 0, 0, time.UTC)
+	zero, many, count := 0, MaxSeriesOccurrences+1, 2
+	before, invalid, far := "2030-01-13", "14/01/2030", "2031-12-31"
+
+	tests := []struct {
+		name string
+		r    Recurrence
+	}{
+		{"frequência desconhecida", Recurrence{Frequency: "daily", Count: &count}},
+		{"sem fim", Recurrence{Frequency: FrequencyWeekly}},
+		{"count e until", Recurrence{Frequency: FrequencyWeekly, Count: &count, Until: &far}},
+		{"count zero", Recurrence{Frequency: FrequencyWeekly, Count: &zero}},
+		{"count acima do limite", Recurrence{Frequency: FrequencyWeekly, Count: &many}},
+		{"until inválido", Recurrence{Frequency: FrequencyWeekly, Until: &invalid}},
+		{"until antes do início", Recurrence{Frequency: FrequencyWeekly, Until: &before}},
+		{"until acima do limite", Recurrence{Frequency: FrequencyWeekly, Until: &far}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.r.Occurrences(first, time.UTC); !errors.Is(err, ErrInvalidRecurrence) {
+				t.Fatalf("erro = %v, esperado ErrInvalidRecurrence", err)
+			}
+		})
+	}
+}
+
+func TestSelectOccurrences(t *testing.T) {
+	now := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	seriesID := uuid.New()
+	occurrence := func(day int, status string) *Appointment {
+		return &Appointment{ID: uuid.New(), SeriesID: &seriesID, StartTime: time.Date(2030, time.January, day, 10, 0, 0, 0, time.UTC), Status: status}
+	}
+	first, second := occurrence(7, StatusPending), occurrence(14, StatusConfirmed)
+	cancelled, last := occurrence(21, StatusCancelledByClient), occurrence(28, StatusPending)
+	series := []*Appointment{last, cancelled, first, second}
+
+	tests := []struct {
+		scope string
+		want  []*Appointment
+	}{
+		{ScopeThis, []*Appointment{second}},
+		{ScopeFollowing, []*Appointment{second, last}},
+		{ScopeAll, []*Appointment{first, second, last}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			got, err := SelectOccurrences(second, series, tt.scope, now)
+			if err != nil {
+				t.Fatalf("SelectOccurrences: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("%d ocorrências, esperado %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i].ID {
+					t.Fatalf("ocorrência %d = %s, esperado %s", i, got[i].StartTime, tt.want[i].StartTime)
+				}
+			}
+		})
+	}
+
+	if _, err := SelectOccurrences(second, series, "next", now); !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("erro = %v, esperado ErrInvalidScope", err)
+	}
+	single := &Appointment{ID: uuid.New(), StartTime: now.Add(time.Hour)}
+	if _, err := SelectOccurrences(single, nil, ScopeAll, now); !errors.Is(err, ErrNotInSeries) {
+		t.Fatalf("erro = %v, esperado ErrNotInSeries", err)
+	}
+}
@@ -0,0 +1,43 @@
+package appointment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session é um horário de um serviço em grupo, com os clientes que reservaram uma vaga.
+// Os agendamentos de uma sessão compartilham profissional, serviço e início.
+type Session struct {
+	ProfessionalID uuid.UUID  `json:"professional_id"`
+	ServiceID      uuid.UUID  `json:"service_id"`
+	StartTime      time.Time  `json:"start_time"`
+	EndTime        time.Time  `json:"end_time"`
+	Capacity       int        `json:"capacity"`
+	SeatsAvailable int        `json:"seats_available"`
+	Attendees      []Attendee `json:"attendees"`
+}
+
+// Attendee é um cliente com vaga na sessão
+type Attendee struct {
+	AppointmentID uuid.UUID `json:"appointment_id"`
+	ClientID      uuid.UUID `json:"client_id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Status        string    `json:"status"`
+	BookedAt      time.Time `json:"booked_at"`
+}
+
+// InSession indica se o agendamento ocupa uma vaga da sessão do serviço que começa em start
+func (a *Appointment) InSession(serviceID uuid.UUID, start time.Time) bool {
+	return a.ServiceID == serviceID && a.StartTime.Equal(start)
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (s *Session) Localize(loc *time.Location) {
+	s.StartTime = s.StartTime.In(loc)
+	s.EndTime = s.EndTime.In(loc)
+	for i := range s.Attendees {
+		s.Attendees[i].BookedAt = s.Attendees[i].BookedAt.In(loc)
+	}
+}
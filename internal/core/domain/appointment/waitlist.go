@@ -0,0 +1,62 @@
+package appointment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidWaitlist é retornado embrulhado com o campo inválido
+	ErrInvalidWaitlist   = errors.New("entrada da lista de espera inválida")
+	ErrWaitlistNotFound  = errors.New("entrada da lista de espera não encontrada")
+	ErrAlreadyWaitlisted = errors.New("o cliente já está na lista de espera deste período")
+	// ErrNoOffer é retornado embrulhado com o status atual da entrada
+	ErrNoOffer = errors.New("não há horário oferecido para esta entrada")
+)
+
+// Status de uma entrada da lista de espera
+const (
+	// WaitlistWaiting aguarda um horário liberado
+	WaitlistWaiting = "waiting"
+	// WaitlistOffered tem um horário reservado temporariamente para o cliente aceitar
+	WaitlistOffered = "offered"
+	WaitlistBooked  = "booked"
+	// WaitlistExpired deixou a oferta expirar sem aceitá-la
+	WaitlistExpired = "expired"
+	// WaitlistLeft saiu da lista a pedido do cliente
+	WaitlistLeft = "left"
+)
+
+// WaitlistEntry registra o interesse de um cliente em um horário do serviço com o profissional entre From e To.
+// Quando um agendamento que cabe no período é cancelado, o horário é oferecido às entradas em ordem de chegada.
+type WaitlistEntry struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	ClientID       uuid.UUID `json:"client_id" gorm:"type:uuid;not null;index"`
+	ProfessionalID uuid.UUID `json:"professional_id" gorm:"type:uuid;not null;index:idx_waitlist_entries_queue"`
+	ServiceID      uuid.UUID `json:"service_id" gorm:"type:uuid;not null;index:idx_waitlist_entries_queue"`
+	From           time.Time `json:"from" gorm:"column:from_time;not null"`
+	To             time.Time `json:"to" gorm:"column:to_time;not null"`
+	Status         string    `json:"status" gorm:"not null;default:'waiting'"`
+	// HoldID é a reserva do horário oferecido; Offer é preenchida enquanto a oferta vale
+	HoldID        *uuid.UUID `json:"-" gorm:"type:uuid"`
+	Offer         *Hold      `json:"offer,omitempty" gorm:"-"`
+	AppointmentID *uuid.UUID `json:"appointment_id,omitempty" gorm:"type:uuid"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Covers indica se o período [start, end) atende à entrada
+func (e *WaitlistEntry) Covers(start, end time.Time) bool {
+	return !start.Before(e.From) && !end.After(e.To)
+}
+
+// Localize converte os horários para o fuso em que serão exibidos
+func (e *WaitlistEntry) Localize(loc *time.Location) {
+	e.From = e.From.In(loc)
+	e.To = e.To.In(loc)
+	e.CreatedAt = e.CreatedAt.In(loc)
+	if e.Offer != nil {
+		e.Offer.Localize(loc)
+	}
+}
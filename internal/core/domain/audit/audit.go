@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActionLoginLockout        = "auth.lockout"
+	ActionImpersonationStart  = "admin.impersonation.start"
+	ActionImpersonatedRequest = "admin.impersonation.request"
+)
+
+// AuditLog registra uma ação sensível para investigação posterior
+type AuditLog struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	Action    string     `json:"action" gorm:"not null;index"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid;index"`
+	SubjectID *uuid.UUID `json:"subject_id,omitempty" gorm:"type:uuid;index"`
+	IP        string     `json:"ip,omitempty"`
+	Details   string     `json:"details,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
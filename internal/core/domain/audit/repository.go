@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Filter restringe a listagem de registros de auditoria; campos vazios não filtram
+type Filter struct {
+	Action    string
+	ActorID   *uuid.UUID
+	SubjectID *uuid.UUID
+	Limit     int
+}
+
+type Repository interface {
+	Create(ctx context.Context, log *AuditLog) error
+	// List retorna os registros mais recentes primeiro
+	List(ctx context.Context, filter Filter) ([]*AuditLog, error)
+}
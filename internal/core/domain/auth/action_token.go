@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposePasswordReset     = "password_reset"
+)
+
+// ActionToken representa um token de uso único enviado por email, persistido apenas como hash
+type ActionToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Purpose   string     `json:"purpose" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
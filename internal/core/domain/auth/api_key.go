@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeAppointmentsWrite = "appointments:write"
+	ScopeServicesRead      = "services:read"
+)
+
+// APIKeyPrefix identifica as chaves de API nos headers de autenticação
+const APIKeyPrefix = "ym_"
+
+// ValidScopes lista os escopos que podem ser concedidos a uma chave de API
+var ValidScopes = []string{
+	ScopeAppointmentsWrite,
+	ScopeServicesRead,
+}
+
+// APIKey representa uma chave de integração servidor a servidor de uma empresa.
+// Apenas o hash da chave é persistido; Prefix permite identificá-la visualmente.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	CompanyID  uuid.UUID  `json:"company_id" gorm:"type:uuid;not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"uniqueIndex;not null"`
+	KeyHash    string     `json:"-" gorm:"not null"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json;not null"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// HasScope indica se a chave foi criada com o escopo informado
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope indica se o escopo pode ser concedido a uma chave
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
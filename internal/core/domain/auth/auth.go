@@ -1,17 +1,38 @@
 package auth
 
-import "youmeet/internal/core/domain/user"
+import (
+	"time"
+
+	"youmeet/internal/core/domain/user"
+)
 
 // AuthRequest representa uma solicitação de autenticação
 type AuthRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	IP       string `json:"-"`
 }
 
 // AuthResponse representa uma resposta de autenticação
+// Quando MFARequired é verdadeiro, apenas ChallengeToken e ExpiresAt são preenchidos
+// e o login deve ser concluído com o segundo fator.
 type AuthResponse struct {
-	Token string     `json:"token"`
-	User  *user.User `json:"user"`
+	Token            string     `json:"token"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshToken     string     `json:"refresh_token"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	MFARequired      bool       `json:"mfa_required"`
+	ChallengeToken   string     `json:"challenge_token,omitempty"`
+	User             *user.User `json:"user"`
+}
+
+// ImpersonationSession representa um token de acesso emitido para um administrador agir como outro usuário.
+// Não há refresh token: ao expirar, uma nova personificação deve ser solicitada.
+type ImpersonationSession struct {
+	Token        string     `json:"token"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	User         *user.User `json:"user"`
+	Impersonator *user.User `json:"impersonator"`
 }
 
 // RegisterRequest representa uma solicitação de registro
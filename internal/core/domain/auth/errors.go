@@ -0,0 +1,39 @@
+package auth
+
+import "errors"
+
+var (
+	ErrInvalidToken = errors.New("token inválido")
+	ErrForbidden    = errors.New("acesso negado")
+
+	ErrInvalidCredentials = errors.New("credenciais inválidas")
+	ErrTooManyAttempts    = errors.New("muitas tentativas de login")
+
+	ErrInvalidRefreshToken  = errors.New("refresh token inválido")
+	ErrInvalidActionToken   = errors.New("token inválido ou expirado")
+	ErrEmailNotVerified     = errors.New("email não verificado")
+	ErrEmailAlreadyVerified = errors.New("email já verificado")
+	// ErrRegistrationFailed não informa o motivo da recusa para não revelar quais emails têm conta
+	ErrRegistrationFailed = errors.New("não foi possível concluir o cadastro com os dados informados")
+
+	ErrInvalidMFACode      = errors.New("código de verificação inválido")
+	ErrMFAAlreadyEnabled   = errors.New("autenticação em dois fatores já está ativa")
+	ErrMFANotEnrolled      = errors.New("autenticação em dois fatores não foi iniciada")
+	ErrMFAEnrollmentNeeded = errors.New("sua empresa exige autenticação em dois fatores")
+
+	ErrInvalidAPIKey  = errors.New("chave de API inválida")
+	ErrInvalidScope   = errors.New("escopo de chave de API inválido")
+	ErrMissingScope   = errors.New("chave de API sem o escopo necessário")
+	ErrAPIKeyNotFound = errors.New("chave de API não encontrada")
+	ErrClientRequired = errors.New("client_id é obrigatório ao usar uma chave de API")
+	ErrInvalidClient  = errors.New("client_id não pertence a um cliente")
+
+	ErrUnknownProvider          = errors.New("provedor de identidade desconhecido")
+	ErrInvalidOAuthState        = errors.New("state de autorização inválido ou expirado")
+	ErrExternalAuthFailed       = errors.New("falha na autenticação com o provedor de identidade")
+	ErrExternalEmailNotVerified = errors.New("o provedor de identidade não confirmou o email da conta")
+	ErrIdentityNotLinked        = errors.New("identidade externa não vinculada")
+
+	ErrImpersonationNotAllowed   = errors.New("não é permitido personificar este usuário")
+	ErrActionDuringImpersonation = errors.New("ação não permitida durante a personificação")
+)
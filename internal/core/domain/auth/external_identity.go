@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity representa a identidade autenticada por um provedor externo
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProvider interface para provedores OAuth2/OIDC usados no login social
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL monta a URL de autorização com o state, o nonce e o desafio PKCE (S256)
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange troca o código pelo ID token, valida-o e retorna a identidade do usuário
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+// UserIdentity vincula uma identidade externa (provedor + subject) a um usuário
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Provider  string    `json:"provider" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `json:"subject" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// OAuthState guarda o nonce e o verificador PKCE de uma autorização até o retorno do provedor.
+// O state é persistido apenas como hash e só pode ser usado uma vez.
+type OAuthState struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	Provider     string     `json:"provider" gorm:"not null"`
+	StateHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	Nonce        string     `json:"-" gorm:"not null"`
+	CodeVerifier string     `json:"-" gorm:"not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
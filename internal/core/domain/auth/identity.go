@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+
+	"youmeet/internal/core/domain/user"
+)
+
+// Identity representa o usuário autenticado e seu perfil de empresa ou profissional
+type Identity struct {
+	User         *user.User
+	Company      *user.Company
+	Professional *user.Professional
+
+	// APIKey é preenchida quando a requisição foi autenticada por chave de API;
+	// nesse caso User e Company referem-se à empresa dona da chave
+	APIKey *APIKey
+
+	// Impersonator é o administrador que está agindo como User, quando a
+	// requisição usa um token de personificação
+	Impersonator *user.User
+
+	// MFAEnrollmentRequired indica um profissional cuja empresa exige 2FA
+	// e que ainda não cadastrou o segundo fator
+	MFAEnrollmentRequired bool
+}
+
+type identityKey struct{}
+
+// WithIdentity retorna uma cópia do contexto carregando a identidade autenticada
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext recupera a identidade autenticada do contexto
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginThrottle acumula as falhas de login de uma chave (email ou IP)
+type LoginThrottle struct {
+	Key           string     `json:"key" gorm:"column:throttle_key;primaryKey"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ThrottleError indica que novas tentativas de login devem aguardar RetryAfter
+type ThrottleError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("muitas tentativas de login; tente novamente em %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottleError) Unwrap() error {
+	return ErrTooManyAttempts
+}
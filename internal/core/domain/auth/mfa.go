@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode representa um código de recuperação de uso único para o segundo fator
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// MFAEnrollment contém o segredo TOTP a ser cadastrado no aplicativo autenticador
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAVerifyRequest representa a segunda etapa do login
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	IP             string `json:"-"`
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken representa um token de renovação persistido apenas como hash.
+// Tokens rotacionados a partir do mesmo login compartilham o FamilyID.
+type RefreshToken struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	FamilyID     uuid.UUID  `json:"family_id" gorm:"type:uuid;not null;index"`
+	TokenHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uuid.UUID `json:"replaced_by_id,omitempty" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
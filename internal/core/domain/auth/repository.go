@@ -2,7 +2,11 @@ package auth
 
 import (
 	"context"
+	"time"
+
 	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
 )
 
 // Service interface para autenticação
@@ -10,4 +14,83 @@ type Service interface {
 	Register(ctx context.Context, req *RegisterRequest) (*user.User, error)
 	Login(ctx context.Context, req *AuthRequest) (*AuthResponse, error)
 	ValidateToken(ctx context.Context, token string) (*user.User, error)
-}
\ No newline at end of file
+	// Authenticate valida o token de acesso e monta a identidade completa do chamador
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+	ResolveIdentity(ctx context.Context, u *user.User) (*Identity, error)
+	Refresh(ctx context.Context, refreshToken string) (*AuthResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+	VerifyMFA(ctx context.Context, req *MFAVerifyRequest) (*AuthResponse, error)
+}
+
+// RefreshTokenRepository interface para persistência dos refresh tokens
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// Revoke revoga o token se ainda estiver ativo e informa se a revogação ocorreu
+	Revoke(ctx context.Context, id uuid.UUID, replacedByID *uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// ActionTokenRepository interface para tokens de verificação de email e redefinição de senha
+type ActionTokenRepository interface {
+	Create(ctx context.Context, token *ActionToken) error
+	GetByHash(ctx context.Context, purpose, tokenHash string) (*ActionToken, error)
+	// MarkUsed consome o token se ainda não foi usado e informa se o consumo ocorreu
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+// LoginThrottleRepository interface para o controle de tentativas de login
+type LoginThrottleRepository interface {
+	// Get retorna o registro da chave ou um registro zerado se ela ainda não teve falhas
+	Get(ctx context.Context, key string) (*LoginThrottle, error)
+	// Update aplica fn ao registro da chave, criado zerado se ainda não existir, e grava o resultado.
+	// A linha fica bloqueada do início ao fim, então falhas simultâneas não se perdem.
+	Update(ctx context.Context, key string, fn func(throttle *LoginThrottle)) (*LoginThrottle, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// RecoveryCodeRepository interface para os códigos de recuperação do segundo fator
+type RecoveryCodeRepository interface {
+	// ReplaceForUser descarta os códigos anteriores do usuário e grava os novos
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []*RecoveryCode) error
+	// Use consome o código se ele pertencer ao usuário e ainda não tiver sido usado
+	Use(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
+
+// APIKeyRepository interface para as chaves de API das empresas
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	ListByCompany(ctx context.Context, companyID uuid.UUID) ([]*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	// Revoke revoga a chave da empresa e informa se ela existia e estava ativa
+	Revoke(ctx context.Context, companyID, id uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// UserIdentityRepository interface para os vínculos com provedores de identidade externos
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+}
+
+// OAuthStateRepository interface para as autorizações OAuth2 em andamento
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *OAuthState) error
+	GetByHash(ctx context.Context, stateHash string) (*OAuthState, error)
+	// MarkUsed consome o state se ainda não foi usado e informa se o consumo ocorreu
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// ImpersonationAuditor interface usada pelos adaptadores de entrada para registrar
+// cada requisição feita com um token de personificação
+type ImpersonationAuditor interface {
+	RecordImpersonatedRequest(ctx context.Context, identity *Identity, method, path, ip string) error
+}
+
+// APIKeyAuthenticator interface usada pelos adaptadores de entrada para autenticar chaves de API
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*Identity, error)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TokenPurposeAccess       = "access"
+	TokenPurposeMFAChallenge = "mfa_challenge"
+)
+
+// Claims representa as informações carregadas por um token assinado
+type Claims struct {
+	UserID    uuid.UUID
+	Role      string
+	Purpose   string
+	ExpiresAt time.Time
+
+	// ImpersonatorID identifica o administrador que age em nome de UserID
+	ImpersonatorID *uuid.UUID
+}
+
+// TokenManager interface para emissão e validação de tokens assinados
+type TokenManager interface {
+	Generate(claims *Claims) (string, error)
+	Parse(token string) (*Claims, error)
+}
@@ -0,0 +1,57 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxKeyLength limita o tamanho da chave enviada no header Idempotency-Key
+const MaxKeyLength = 255
+
+var (
+	ErrInvalidKey = errors.New("Idempotency-Key deve ter no máximo 255 caracteres")
+	ErrKeyReused  = errors.New("a Idempotency-Key já foi usada com outra requisição")
+	// ErrInProgress indica uma repetição que chegou antes de a primeira requisição terminar
+	ErrInProgress = errors.New("a requisição com esta Idempotency-Key ainda está em andamento")
+	ErrNotFound   = errors.New("chave de idempotência não encontrada")
+	// ErrKeyExists é retornado pelo repositório quando outra requisição gravou a chave primeiro
+	ErrKeyExists = errors.New("chave de idempotência já registrada")
+)
+
+// Record guarda a primeira requisição feita com uma chave e, depois de concluída, a resposta
+// devolvida às repetições até ExpiresAt
+type Record struct {
+	// Key é a chave do cliente prefixada por quem a enviou (usuário ou chave de API)
+	Key string `gorm:"column:idempotency_key;primaryKey"`
+	// Fingerprint resume o método, o caminho e o corpo da requisição
+	Fingerprint string `gorm:"not null"`
+	// StatusCode fica zerado enquanto a primeira requisição não termina
+	StatusCode int `gorm:"not null;default:0"`
+	// LockedUntil é até quando a requisição em andamento detém a chave; depois disso uma repetição
+	// assume o registro, para que uma requisição interrompida não prenda a chave até ExpiresAt
+	LockedUntil time.Time
+	// LeaseToken identifica quem detém a requisição em andamento; só ele grava a resposta ou libera a chave
+	LeaseToken  string
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed indica se a resposta da primeira requisição já foi gravada
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Abandoned indica uma requisição em andamento cujo prazo para responder terminou
+func (r *Record) Abandoned(now time.Time) bool {
+	return !r.Completed() && !now.Before(r.LockedUntil)
+}
+
+func (r *Record) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
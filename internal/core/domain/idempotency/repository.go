@@ -0,0 +1,21 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Get(ctx context.Context, key string) (*Record, error)
+	// Create grava o registro; retorna ErrKeyExists se a chave já estiver registrada
+	Create(ctx context.Context, record *Record) error
+	// TakeOver passa a leaseToken, até lockedUntil, uma requisição em andamento com prazo vencido em now;
+	// retorna false se a resposta já foi gravada ou se outra repetição assumiu o registro primeiro
+	TakeOver(ctx context.Context, key, leaseToken string, lockedUntil, now time.Time) (bool, error)
+	// Complete grava a resposta se record.LeaseToken ainda detiver a requisição em andamento
+	Complete(ctx context.Context, record *Record) error
+	// Release apaga o registro se leaseToken ainda detiver a requisição em andamento
+	Release(ctx context.Context, key, leaseToken string) error
+	Delete(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
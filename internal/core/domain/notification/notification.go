@@ -0,0 +1,8 @@
+package notification
+
+// Email representa uma mensagem a ser entregue a um usuário
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
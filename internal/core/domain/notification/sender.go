@@ -0,0 +1,8 @@
+package notification
+
+import "context"
+
+// EmailSender interface para entrega de emails
+type EmailSender interface {
+	Send(ctx context.Context, email *Email) error
+}
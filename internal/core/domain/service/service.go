@@ -1,16 +1,93 @@
 package service
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrNotFound                = errors.New("serviço não encontrado")
+	ErrProfessionalNotOffering = errors.New("o profissional não atende este serviço")
+)
+
 type Service struct {
-	ID              uuid.UUID   `json:"id" gorm:"primaryKey;type:uuid"`
-	Name            string      `json:"name" gorm:"not null"`
-	Description     string      `json:"description"`
-	Duration        int         `json:"duration" gorm:"not null"`
-	Price           float64     `json:"price" gorm:"not null"`
-	ProfessionalIDs []uuid.UUID `json:"professional_ids" gorm:"type:uuid[]"`
-	CreatedAt       time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	ID              uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	Name            string    `json:"name" gorm:"not null"`
+	Description     string    `json:"description"`
+	Duration        int       `json:"duration" gorm:"not null"`
+	Price           float64   `json:"price" gorm:"not null"`
+	ProfessionalIDs UUIDList  `json:"professional_ids" gorm:"type:uuid[]"`
+	// Capacity é o número de clientes por horário; acima de 1, o serviço é uma sessão em grupo
+	Capacity  int       `json:"capacity" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// Seats retorna as vagas de cada horário, tratando capacidades não definidas como atendimento individual
+func (s *Service) Seats() int {
+	if s.Capacity < 1 {
+		return 1
+	}
+	return s.Capacity
+}
+
+// IsGroup indica se vários clientes podem reservar o mesmo horário com o profissional
+func (s *Service) IsGroup() bool {
+	return s.Seats() > 1
+}
+
+// OfferedBy indica se o profissional atende o serviço
+func (s *Service) OfferedBy(professionalID uuid.UUID) bool {
+	for _, id := range s.ProfessionalIDs {
+		if id == professionalID {
+			return true
+		}
+	}
+	return false
+}
+
+// UUIDList é gravada no formato de array do PostgreSQL ({a,b}), que o SQLite armazena como texto
+type UUIDList []uuid.UUID
+
+func (l UUIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	ids := make([]string, len(l))
+	for i, id := range l {
+		ids[i] = id.String()
+	}
+	return "{" + strings.Join(ids, ",") + "}", nil
+}
+
+func (l *UUIDList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("tipo não suportado para UUIDList: %T", value)
+	}
+
+	raw = strings.Trim(raw, "{}")
+	list := UUIDList{}
+	if raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				return err
+			}
+			list = append(list, id)
+		}
+	}
+	*l = list
+	return nil
 }
\ No newline at end of file
@@ -8,14 +8,19 @@ import (
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*User, error)
 }
 
 type CompanyRepository interface {
 	CreateCompany(ctx context.Context, company *Company) error
 	GetCompanyByID(ctx context.Context, id uuid.UUID) (*Company, error)
 	GetCompanyByUserID(ctx context.Context, userID uuid.UUID) (*Company, error)
+	SetRequireProfessionalMFA(ctx context.Context, companyID uuid.UUID, required bool) error
+	SetTimeZone(ctx context.Context, companyID uuid.UUID, timeZone string) error
 }
 
 type ProfessionalRepository interface {
@@ -23,4 +28,6 @@ type ProfessionalRepository interface {
 	GetProfessionalByID(ctx context.Context, id uuid.UUID) (*Professional, error)
 	GetProfessionalByUserID(ctx context.Context, userID uuid.UUID) (*Professional, error)
 	ListByCompanyID(ctx context.Context, companyID uuid.UUID) ([]*Professional, error)
+	ListProfessionalsByIDs(ctx context.Context, ids []uuid.UUID) ([]*Professional, error)
+	SetTimeZone(ctx context.Context, professionalID uuid.UUID, timeZone string) error
 }
@@ -1,25 +1,45 @@
 package user
 
 import (
+	"errors"
+	"fmt"
 	"time"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrNotFound             = errors.New("usuário não encontrado")
+	ErrCompanyNotFound      = errors.New("empresa não encontrada")
+	ErrProfessionalNotFound = errors.New("profissional não encontrado")
+	ErrInvalidTimeZone      = errors.New("fuso horário inválido")
+)
+
 type User struct {
-	ID           uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
-	Name         string    `json:"name" gorm:"not null"`
-	Email        string    `json:"email" gorm:"uniqueIndex;not null"`
-	PasswordHash string    `json:"-" gorm:"not null"`
-	Role         string    `json:"role" gorm:"not null"`
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ID              uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	Name            string     `json:"name" gorm:"not null"`
+	Email           string     `json:"email" gorm:"uniqueIndex;not null"`
+	PasswordHash    string     `json:"-" gorm:"not null"`
+	Role            string     `json:"role" gorm:"not null"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	TOTPSecret      string     `json:"-"`
+	TOTPEnabled     bool       `json:"totp_enabled" gorm:"not null;default:false"`
+	TOTPLastStep    int64      `json:"-" gorm:"not null;default:0"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IsEmailVerified indica se o usuário já confirmou o email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 type Company struct {
-	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	Name      string    `json:"name" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	User      User      `gorm:"foreignKey:UserID"`
+	ID                     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID                 uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Name                   string    `json:"name" gorm:"not null"`
+	RequireProfessionalMFA bool      `json:"require_professional_mfa" gorm:"not null;default:false"`
+	TimeZone               string    `json:"time_zone,omitempty"`
+	CreatedAt              time.Time `json:"created_at" gorm:"autoCreateTime"`
+	User                   User      `gorm:"foreignKey:UserID"`
 }
 
 type Professional struct {
@@ -27,7 +47,43 @@ type Professional struct {
 	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
 	Name      string     `json:"name" gorm:"not null"`
 	CompanyID *uuid.UUID `json:"company_id,omitempty" gorm:"type:uuid"`
+	TimeZone  string     `json:"time_zone,omitempty"`
 	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	User      User       `gorm:"foreignKey:UserID"`
 	Company   *Company   `gorm:"foreignKey:CompanyID"`
-}
\ No newline at end of file
+}
+
+// Location retorna o fuso em que a agenda do profissional é interpretada: o dele,
+// o da empresa (quando carregada) ou UTC
+func (p *Professional) Location() *time.Location {
+	if p.TimeZone != "" {
+		if loc, err := LoadLocation(p.TimeZone); err == nil {
+			return loc
+		}
+	}
+	if p.Company != nil {
+		return p.Company.Location()
+	}
+	return time.UTC
+}
+
+// Location retorna o fuso da empresa, ou UTC quando não definido
+func (c *Company) Location() *time.Location {
+	if loc, err := LoadLocation(c.TimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// LoadLocation interpreta um fuso IANA (ex.: America/Sao_Paulo). Vazio significa UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	// "Local" dependeria da configuração do servidor
+	if name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return loc, nil
+}
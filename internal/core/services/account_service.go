@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/notification"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountSettings agrupa a validade dos tokens enviados por email e a base dos links
+type AccountSettings struct {
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	LinkBaseURL          string
+}
+
+type AccountService struct {
+	userRepo    user.UserRepository
+	actionRepo  auth.ActionTokenRepository
+	refreshRepo auth.RefreshTokenRepository
+	sender      notification.EmailSender
+	settings    AccountSettings
+}
+
+func NewAccountService(userRepo user.UserRepository, actionRepo auth.ActionTokenRepository, refreshRepo auth.RefreshTokenRepository, sender notification.EmailSender, settings AccountSettings) *AccountService {
+	return &AccountService{
+		userRepo:    userRepo,
+		actionRepo:  actionRepo,
+		refreshRepo: refreshRepo,
+		sender:      sender,
+		settings:    settings,
+	}
+}
+
+// RequestEmailVerification envia um novo link de verificação, invalidando os anteriores
+func (s *AccountService) RequestEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.IsEmailVerified() {
+		return auth.ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueActionToken(ctx, u, auth.PurposeEmailVerification, s.settings.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &notification.Email{
+		To:      u.Email,
+		Subject: "Confirme seu email no YouMeet",
+		Body: fmt.Sprintf("Olá, %s!\n\nConfirme seu email acessando o link abaixo:\n%s\n\nO link expira em %s.",
+			u.Name, s.link("/verify-email", token), s.settings.EmailVerificationTTL),
+	})
+}
+
+// ConfirmEmail consome o token de verificação e marca o email do usuário como verificado
+func (s *AccountService) ConfirmEmail(ctx context.Context, token string) error {
+	u, err := s.consumeActionToken(ctx, auth.PurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+
+	if u.IsEmailVerified() {
+		return nil
+	}
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	return s.userRepo.Update(ctx, u)
+}
+
+// RequestPasswordReset envia um link de redefinição de senha.
+// Emails desconhecidos não geram erro para não revelar quais contas existem.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueActionToken(ctx, u, auth.PurposePasswordReset, s.settings.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &notification.Email{
+		To:      u.Email,
+		Subject: "Redefinição de senha do YouMeet",
+		Body: fmt.Sprintf("Olá, %s!\n\nPara redefinir sua senha, acesse o link abaixo:\n%s\n\nO link expira em %s. Se você não solicitou a redefinição, ignore este email.",
+			u.Name, s.link("/reset-password", token), s.settings.PasswordResetTTL),
+	})
+}
+
+// ResetPassword consome o token de redefinição, troca a senha e encerra todas as sessões
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	u, err := s.consumeActionToken(ctx, auth.PurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hashedPassword)
+
+	// Receber o link prova a posse do email
+	if !u.IsEmailVerified() {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return err
+	}
+	return s.refreshRepo.RevokeAllForUser(ctx, u.ID)
+}
+
+func (s *AccountService) issueActionToken(ctx context.Context, u *user.User, purpose string, ttl time.Duration) (string, error) {
+	if err := s.actionRepo.InvalidateForUser(ctx, u.ID, purpose); err != nil {
+		return "", err
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = s.actionRepo.Create(ctx, &auth.ActionToken{
+		ID:        uuid.New(),
+		UserID:    u.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *AccountService) consumeActionToken(ctx context.Context, purpose, raw string) (*user.User, error) {
+	token, err := s.actionRepo.GetByHash(ctx, purpose, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, auth.ErrInvalidActionToken
+	}
+
+	used, err := s.actionRepo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, auth.ErrInvalidActionToken
+	}
+
+	return s.userRepo.GetByID(ctx, token.UserID)
+}
+
+func (s *AccountService) link(path, token string) string {
+	return s.settings.LinkBaseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/services"
+)
+
+func newAccountService(f *authFixture, sender *outbox) *services.AccountService {
+	return services.NewAccountService(f.userRepo, repositories.NewActionTokenRepository(f.db), repositories.NewRefreshTokenRepository(f.db), sender, services.AccountSettings{
+		EmailVerificationTTL: 24 * time.Hour,
+		PasswordResetTTL:     time.Hour,
+		LinkBaseURL:          "http://localhost:3000",
+	})
+}
+
+func TestEmailVerification(t *testing.T) {
+	f := newAuthFixture(t)
+	sender := &outbox{}
+	accounts := newAccountService(f, sender)
+	ctx := context.Background()
+	resp := f.login(t, "ana@example.com", "client")
+
+	if err := accounts.RequestEmailVerification(ctx, resp.User.ID); err != nil {
+		t.Fatalf("RequestEmailVerification: %v", err)
+	}
+	first := sender.lastToken(t)
+	// Um novo pedido invalida o link anterior
+	if err := accounts.RequestEmailVerification(ctx, resp.User.ID); err != nil {
+		t.Fatalf("RequestEmailVerification: %v", err)
+	}
+	if err := accounts.ConfirmEmail(ctx, first); !errors.Is(err, auth.ErrInvalidActionToken) {
+		t.Fatalf("link substituído: erro = %v, esperado %v", err, auth.ErrInvalidActionToken)
+	}
+
+	if err := accounts.ConfirmEmail(ctx, sender.lastToken(t)); err != nil {
+		t.Fatalf("ConfirmEmail: %v", err)
+	}
+	u, err := f.userRepo.GetByID(ctx, resp.User.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !u.IsEmailVerified() {
+		t.Fatal("o email não foi marcado como verificado")
+	}
+	if err := accounts.RequestEmailVerification(ctx, resp.User.ID); !errors.Is(err, auth.ErrEmailAlreadyVerified) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrEmailAlreadyVerified)
+	}
+}
+
+func TestPasswordResetIgnoresUnknownEmail(t *testing.T) {
+	f := newAuthFixture(t)
+	sender := &outbox{}
+	accounts := newAccountService(f, sender)
+
+	if err := accounts.RequestPasswordReset(context.Background(), "ninguem@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	if len(sender.sent()) != 0 {
+		t.Fatal("um email foi enviado para um endereço sem conta")
+	}
+}
+
+func TestPasswordResetChangesPasswordAndEndsSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	sender := &outbox{}
+	accounts := newAccountService(f, sender)
+	ctx := context.Background()
+	session := f.login(t, "ana@example.com", "client")
+
+	if err := accounts.RequestPasswordReset(ctx, "ana@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	token := sender.lastToken(t)
+	if err := accounts.ResetPassword(ctx, token, "nova-senha-segura"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := accounts.ResetPassword(ctx, token, "outra-senha-segura"); !errors.Is(err, auth.ErrInvalidActionToken) {
+		t.Fatalf("link reutilizado: erro = %v, esperado %v", err, auth.ErrInvalidActionToken)
+	}
+
+	if _, err := f.service.Refresh(ctx, session.RefreshToken); !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Fatalf("sessão anterior à troca de senha: erro = %v, esperado %v", err, auth.ErrInvalidRefreshToken)
+	}
+	if _, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123"}); err == nil {
+		t.Fatal("a senha antiga ainda é aceita")
+	}
+	resp, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "nova-senha-segura"})
+	if err != nil {
+		t.Fatalf("login com a nova senha: %v", err)
+	}
+	if !resp.User.IsEmailVerified() {
+		t.Fatal("receber o link de redefinição deveria confirmar o email")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const maxAuditLogs = 500
+
+// AdminService reúne as operações da equipe de suporte da plataforma
+type AdminService struct {
+	userRepo         user.UserRepository
+	auditRepo        audit.Repository
+	tokens           auth.TokenManager
+	impersonationTTL time.Duration
+}
+
+func NewAdminService(userRepo user.UserRepository, auditRepo audit.Repository, tokens auth.TokenManager, impersonationTTL time.Duration) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		tokens:           tokens,
+		impersonationTTL: impersonationTTL,
+	}
+}
+
+// EnsureAdmin cria a conta de administrador configurada caso ela ainda não exista.
+// A role admin não pode ser obtida pelo cadastro público.
+func (s *AdminService) EnsureAdmin(ctx context.Context, name, email, password string) error {
+	existing, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		if existing.Role != "admin" {
+			return fmt.Errorf("o email %s já pertence a uma conta que não é de administrador", email)
+		}
+		return nil
+	}
+	if !errors.Is(err, user.ErrNotFound) {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.userRepo.Create(ctx, &user.User{
+		ID:              uuid.New(),
+		Name:            name,
+		Email:           email,
+		PasswordHash:    string(hashedPassword),
+		Role:            "admin",
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
+	})
+}
+
+// Impersonate emite um token de acesso curto para o administrador agir como o usuário informado
+func (s *AdminService) Impersonate(ctx context.Context, admin *user.User, targetID uuid.UUID, reason, ip string) (*auth.ImpersonationSession, error) {
+	target, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == admin.ID || target.Role == "admin" {
+		return nil, auth.ErrImpersonationNotAllowed
+	}
+
+	// O início da personificação é auditado antes de o token existir
+	if err := s.auditRepo.Create(ctx, &audit.AuditLog{
+		ID:        uuid.New(),
+		Action:    audit.ActionImpersonationStart,
+		ActorID:   &admin.ID,
+		SubjectID: &target.ID,
+		IP:        ip,
+		Details:   reason,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.impersonationTTL)
+	token, err := s.tokens.Generate(&auth.Claims{
+		UserID:         target.ID,
+		Role:           target.Role,
+		Purpose:        auth.TokenPurposeAccess,
+		ExpiresAt:      expiresAt,
+		ImpersonatorID: &admin.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.ImpersonationSession{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		User:         target,
+		Impersonator: admin,
+	}, nil
+}
+
+// RecordImpersonatedRequest registra uma requisição feita com token de personificação
+func (s *AdminService) RecordImpersonatedRequest(ctx context.Context, identity *auth.Identity, method, path, ip string) error {
+	return s.auditRepo.Create(ctx, &audit.AuditLog{
+		ID:        uuid.New(),
+		Action:    audit.ActionImpersonatedRequest,
+		ActorID:   &identity.Impersonator.ID,
+		SubjectID: &identity.User.ID,
+		IP:        ip,
+		Details:   method + " " + path,
+		CreatedAt: time.Now(),
+	})
+}
+
+// ListAuditLogs consulta a trilha de auditoria
+func (s *AdminService) ListAuditLogs(ctx context.Context, filter audit.Filter) ([]*audit.AuditLog, error) {
+	if filter.Limit <= 0 || filter.Limit > maxAuditLogs {
+		filter.Limit = maxAuditLogs
+	}
+	return s.auditRepo.List(ctx, filter)
+}
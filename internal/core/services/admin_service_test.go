@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+)
+
+func newAdminService(t *testing.T, f *authFixture) (*services.AdminService, *user.User) {
+	t.Helper()
+	ctx := context.Background()
+	admins := services.NewAdminService(f.userRepo, repositories.NewAuditRepository(f.db), f.tokens, 15*time.Minute)
+
+	if err := admins.EnsureAdmin(ctx, "Suporte", "suporte@example.com", "senha-do-suporte"); err != nil {
+		t.Fatalf("EnsureAdmin: %v", err)
+	}
+	// Executar de novo não duplica a conta
+	if err := admins.EnsureAdmin(ctx, "Suporte", "suporte@example.com", "senha-do-suporte"); err != nil {
+		t.Fatalf("EnsureAdmin repetido: %v", err)
+	}
+	admin, err := f.userRepo.GetByEmail(ctx, "suporte@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	return admins, admin
+}
+
+func TestImpersonationIsAudited(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	admins, admin := newAdminService(t, f)
+	target := f.login(t, "ana@example.com", "client").User
+
+	session, err := admins.Impersonate(ctx, admin, target.ID, "chamado 123", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+	if session.ExpiresAt.After(time.Now().Add(15 * time.Minute)) {
+		t.Fatalf("token expira em %s, esperado no máximo 15 minutos", session.ExpiresAt)
+	}
+
+	identity, err := f.service.Authenticate(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if identity.User.ID != target.ID || identity.Impersonator == nil || identity.Impersonator.ID != admin.ID {
+		t.Fatalf("identidade = %+v, esperado o alvo personificado pelo administrador", identity)
+	}
+
+	if err := admins.RecordImpersonatedRequest(ctx, identity, "GET", "/appointments", "10.0.0.1"); err != nil {
+		t.Fatalf("RecordImpersonatedRequest: %v", err)
+	}
+
+	logs, err := admins.ListAuditLogs(ctx, audit.Filter{ActorID: &admin.ID})
+	if err != nil {
+		t.Fatalf("ListAuditLogs: %v", err)
+	}
+	actions := map[string]bool{}
+	for _, log := range logs {
+		if log.SubjectID == nil || *log.SubjectID != target.ID {
+			t.Fatalf("registro %s sem o usuário personificado", log.Action)
+		}
+		actions[log.Action] = true
+	}
+	if len(logs) != 2 || !actions[audit.ActionImpersonationStart] || !actions[audit.ActionImpersonatedRequest] {
+		t.Fatalf("auditoria = %+v, esperado o início e a requisição", logs)
+	}
+}
+
+func TestImpersonationNotAllowed(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	admins, admin := newAdminService(t, f)
+
+	if _, err := admins.Impersonate(ctx, admin, admin.ID, "teste", ""); !errors.Is(err, auth.ErrImpersonationNotAllowed) {
+		t.Fatalf("erro = %v, esperado ErrImpersonationNotAllowed para o próprio administrador", err)
+	}
+
+	other := f.login(t, "outro-admin@example.com", "admin").User
+	if _, err := admins.Impersonate(ctx, admin, other.ID, "teste", ""); !errors.Is(err, auth.ErrImpersonationNotAllowed) {
+		t.Fatalf("erro = %v, esperado ErrImpersonationNotAllowed para outro administrador", err)
+	}
+
+	logs, err := admins.ListAuditLogs(ctx, audit.Filter{Action: audit.ActionImpersonationStart})
+	if err != nil {
+		t.Fatalf("ListAuditLogs: %v", err)
+	}
+	if len(logs) != 0 {
+		t.Fatalf("%d personificações auditadas, esperado nenhuma", len(logs))
+	}
+}
+
+func TestImpersonationTokenRequiresActiveAdmin(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	admins, admin := newAdminService(t, f)
+	target := f.login(t, "ana@example.com", "client").User
+
+	session, err := admins.Impersonate(ctx, admin, target.ID, "chamado 123", "")
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+
+	// O token deixa de valer quando o administrador perde a role
+	admin.Role = "client"
+	if err := f.userRepo.Update(ctx, admin); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := f.service.Authenticate(ctx, session.Token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Intervalo mínimo entre duas gravações de LastUsedAt, para não escrever a cada requisição
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyService struct {
+	apiKeyRepo  auth.APIKeyRepository
+	companyRepo user.CompanyRepository
+	userRepo    user.UserRepository
+}
+
+func NewAPIKeyService(apiKeyRepo auth.APIKeyRepository, companyRepo user.CompanyRepository, userRepo user.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  apiKeyRepo,
+		companyRepo: companyRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// Create gera uma nova chave para a empresa. A chave completa é retornada apenas aqui.
+func (s *APIKeyService) Create(ctx context.Context, companyID uuid.UUID, name string, scopes []string) (*auth.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", auth.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !auth.IsValidScope(scope) {
+			return nil, "", auth.ErrInvalidScope
+		}
+	}
+
+	prefixBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, "", err
+	}
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := auth.APIKeyPrefix + hex.EncodeToString(prefixBytes)
+	raw := prefix + "_" + secret
+
+	key := &auth.APIKey{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(raw),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, raw, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, companyID uuid.UUID) ([]*auth.APIKey, error) {
+	return s.apiKeyRepo.ListByCompany(ctx, companyID)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, companyID, id uuid.UUID) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, companyID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return auth.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate valida a chave e retorna a identidade da empresa dona dela
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (*auth.Identity, error) {
+	if !strings.HasPrefix(raw, auth.APIKeyPrefix) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	prefix, _, found := strings.Cut(strings.TrimPrefix(raw, auth.APIKeyPrefix), "_")
+	if !found {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByPrefix(ctx, auth.APIKeyPrefix+prefix)
+	if err != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(raw))) != 1 || key.RevokedAt != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	company, err := s.companyRepo.GetCompanyByID(ctx, key.CompanyID)
+	if err != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	owner, err := s.userRepo.GetByID(ctx, company.UserID)
+	if err != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+
+	return &auth.Identity{
+		User:    owner,
+		Company: company,
+		APIKey:  key,
+	}, nil
+}
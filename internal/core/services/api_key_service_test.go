@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+// newAPIKeyService cria uma empresa com dono e o serviço de chaves de API
+func newAPIKeyService(t *testing.T) (*services.APIKeyService, *user.Company) {
+	t.Helper()
+	ctx := context.Background()
+	db := newTestDB(t)
+	userRepo := repositories.NewUserRepository(db)
+	companyRepo := repositories.NewCompanyRepository(db)
+
+	owner := &user.User{ID: uuid.New(), Name: "Clínica", Email: "clinica@example.com", Role: "company", CreatedAt: time.Now()}
+	if err := userRepo.Create(ctx, owner); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	company := &user.Company{ID: uuid.New(), UserID: owner.ID, Name: "Clínica"}
+	if err := companyRepo.CreateCompany(ctx, company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+
+	return services.NewAPIKeyService(repositories.NewAPIKeyRepository(db), companyRepo, userRepo), company
+}
+
+func TestAPIKeyAuthenticatesAsCompany(t *testing.T) {
+	apiKeys, company := newAPIKeyService(t)
+	ctx := context.Background()
+
+	key, raw, err := apiKeys.Create(ctx, company.ID, "integração", []string{auth.ScopeAppointmentsWrite})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(raw, key.Prefix+"_") || key.KeyHash == raw {
+		t.Fatalf("chave %q não corresponde ao prefixo %q ou foi guardada em claro", raw, key.Prefix)
+	}
+
+	identity, err := apiKeys.Authenticate(ctx, raw)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if identity.Company == nil || identity.Company.ID != company.ID || identity.APIKey == nil {
+		t.Fatalf("identidade = %+v, esperada a empresa da chave", identity)
+	}
+	if !identity.APIKey.HasScope(auth.ScopeAppointmentsWrite) || identity.APIKey.HasScope(auth.ScopeServicesRead) {
+		t.Fatalf("escopos = %v, esperado apenas %s", identity.APIKey.Scopes, auth.ScopeAppointmentsWrite)
+	}
+
+	// Um segredo diferente com o mesmo prefixo não é aceito
+	if _, err := apiKeys.Authenticate(ctx, key.Prefix+"_outro-segredo"); !errors.Is(err, auth.ErrInvalidAPIKey) {
+		t.Fatalf("erro = %v, esperado ErrInvalidAPIKey", err)
+	}
+}
+
+func TestAPIKeyRejectsInvalidScopes(t *testing.T) {
+	apiKeys, company := newAPIKeyService(t)
+
+	for _, scopes := range [][]string{nil, {"admin"}, {auth.ScopeServicesRead, "appointments:*"}} {
+		if _, _, err := apiKeys.Create(context.Background(), company.ID, "integração", scopes); !errors.Is(err, auth.ErrInvalidScope) {
+			t.Fatalf("escopos %v: erro = %v, esperado ErrInvalidScope", scopes, err)
+		}
+	}
+}
+
+func TestRevokedAPIKeyIsRejected(t *testing.T) {
+	apiKeys, company := newAPIKeyService(t)
+	ctx := context.Background()
+
+	key, raw, err := apiKeys.Create(ctx, company.ID, "integração", []string{auth.ScopeServicesRead})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Outra empresa não revoga a chave
+	if err := apiKeys.Revoke(ctx, uuid.New(), key.ID); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Fatalf("erro = %v, esperado ErrAPIKeyNotFound", err)
+	}
+	if err := apiKeys.Revoke(ctx, company.ID, key.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := apiKeys.Authenticate(ctx, raw); !errors.Is(err, auth.ErrInvalidAPIKey) {
+		t.Fatalf("erro = %v, esperado ErrInvalidAPIKey para chave revogada", err)
+	}
+	if err := apiKeys.Revoke(ctx, company.ID, key.ID); !errors.Is(err, auth.ErrAPIKeyNotFound) {
+		t.Fatalf("erro = %v, esperado ErrAPIKeyNotFound ao revogar de novo", err)
+	}
+}
@@ -12,33 +12,59 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// AuthSettings agrupa os parâmetros de emissão de tokens
+type AuthSettings struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MFAChallengeTTL time.Duration
+}
+
 type AuthService struct {
 	userRepo    user.UserRepository
 	companyRepo user.CompanyRepository
 	profRepo    user.ProfessionalRepository
+	refreshRepo auth.RefreshTokenRepository
+	tokens      auth.TokenManager
+	loginGuard  *LoginGuard
+	mfa         *MFAService
+	settings    AuthSettings
+
+	// Hash usado quando o email não existe, para que o login leve o mesmo tempo
+	dummyHash []byte
 }
 
-func NewAuthService(userRepo user.UserRepository, companyRepo user.CompanyRepository, profRepo user.ProfessionalRepository) *AuthService {
+func NewAuthService(userRepo user.UserRepository, companyRepo user.CompanyRepository, profRepo user.ProfessionalRepository, refreshRepo auth.RefreshTokenRepository, tokens auth.TokenManager, loginGuard *LoginGuard, mfa *MFAService, settings AuthSettings) *AuthService {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+
 	return &AuthService{
 		userRepo:    userRepo,
 		companyRepo: companyRepo,
 		profRepo:    profRepo,
+		refreshRepo: refreshRepo,
+		tokens:      tokens,
+		loginGuard:  loginGuard,
+		mfa:         mfa,
+		settings:    settings,
+		dummyHash:   dummyHash,
 	}
 }
 
 func (s *AuthService) Register(ctx context.Context, req *auth.RegisterRequest) (*user.User, error) {
 	name, email, password, role := req.Name, req.Email, req.Password, req.Role
-	// Verificar se usuário já existe
-	existingUser, _ := s.userRepo.GetByEmail(ctx, email)
-	if existingUser != nil {
-		return nil, errors.New("usuário já existe")
-	}
-
 	// Validar role
 	if role != "client" && role != "company" && role != "professional" {
 		return nil, errors.New("role inválido")
 	}
 
+	// Verificar se usuário já existe, sem revelar ao chamador que o email tem conta
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return nil, auth.ErrRegistrationFailed
+	}
+	if !errors.Is(err, user.ErrNotFound) {
+		return nil, err
+	}
+
 	// Hash da senha
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -57,56 +83,323 @@ func (s *AuthService) Register(ctx context.Context, req *auth.RegisterRequest) (
 
 	err = s.userRepo.Create(ctx, u)
 	if err != nil {
+		// Um cadastro simultâneo com o mesmo email gravou primeiro
+		if _, getErr := s.userRepo.GetByEmail(ctx, email); getErr == nil {
+			return nil, auth.ErrRegistrationFailed
+		}
+		return nil, err
+	}
+
+	// Criar perfis específicos baseado no role; sem o perfil, a conta não é mantida
+	if err := s.createProfile(ctx, u); err != nil {
+		if deleteErr := s.userRepo.Delete(ctx, u.ID); deleteErr != nil {
+			return nil, errors.Join(err, deleteErr)
+		}
 		return nil, err
 	}
 
-	// Criar perfis específicos baseado no role
-	switch role {
+	return u, nil
+}
+
+// createProfile cria o perfil de empresa ou de profissional autônomo do usuário recém-cadastrado
+func (s *AuthService) createProfile(ctx context.Context, u *user.User) error {
+	switch u.Role {
 	case "company":
 		company := &user.Company{
 			ID:        uuid.New(),
 			UserID:    u.ID,
-			Name:      name,
+			Name:      u.Name,
 			CreatedAt: time.Now(),
 		}
-		s.companyRepo.CreateCompany(ctx, company)
+		return s.companyRepo.CreateCompany(ctx, company)
 
 	case "professional":
 		professional := &user.Professional{
 			ID:        uuid.New(),
 			UserID:    u.ID,
-			Name:      name,
+			Name:      u.Name,
 			CompanyID: nil, // Autônomo
 			CreatedAt: time.Now(),
 		}
-		s.profRepo.CreateProfessional(ctx, professional)
+		return s.profRepo.CreateProfessional(ctx, professional)
 	}
 
-	return u, nil
+	return nil
 }
 
 func (s *AuthService) Login(ctx context.Context, req *auth.AuthRequest) (*auth.AuthResponse, error) {
 	email, password := req.Email, req.Password
-	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err := s.loginGuard.Check(ctx, email, req.IP); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, user.ErrNotFound) {
+		return nil, err
+	}
+
+	// Sempre executa o bcrypt para não revelar quais emails existem
+	passwordHash := s.dummyHash
+	if u != nil {
+		passwordHash = []byte(u.PasswordHash)
+	}
+	err = bcrypt.CompareHashAndPassword(passwordHash, []byte(password))
+	if err != nil || u == nil {
+		if err := s.loginGuard.RecordFailure(ctx, email, req.IP); err != nil {
+			return nil, err
+		}
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if err := s.loginGuard.RecordSuccess(ctx, email); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, u)
+}
+
+// startSession conclui um login primário (senha ou provedor externo)
+func (s *AuthService) startSession(ctx context.Context, u *user.User) (*auth.AuthResponse, error) {
+	// Com 2FA ativo, a sessão só é emitida após a verificação do código
+	if u.TOTPEnabled {
+		return s.issueMFAChallenge(u)
+	}
+
+	// Cada login inicia uma nova família de refresh tokens
+	return s.issueSession(ctx, u, uuid.New())
+}
+
+// VerifyMFA conclui o login validando o token de desafio e o código TOTP ou de recuperação
+func (s *AuthService) VerifyMFA(ctx context.Context, req *auth.MFAVerifyRequest) (*auth.AuthResponse, error) {
+	claims, err := s.tokens.Parse(req.ChallengeToken)
+	if err != nil || claims.Purpose != auth.TokenPurposeMFAChallenge {
+		return nil, auth.ErrInvalidToken
+	}
+
+	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	// Códigos errados contam como falhas de login do mesmo email
+	if err := s.loginGuard.Check(ctx, u.Email, req.IP); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.VerifyCode(ctx, u, req.Code); err != nil {
+		if errors.Is(err, auth.ErrInvalidMFACode) {
+			if err := s.loginGuard.RecordFailure(ctx, u.Email, req.IP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.loginGuard.RecordSuccess(ctx, u.Email); err != nil {
+		return nil, err
+	}
+	return s.issueSession(ctx, u, uuid.New())
+}
+
+func (s *AuthService) issueMFAChallenge(u *user.User) (*auth.AuthResponse, error) {
+	expiresAt := time.Now().Add(s.settings.MFAChallengeTTL)
+	challenge, err := s.tokens.Generate(&auth.Claims{
+		UserID:    u.ID,
+		Role:      u.Role,
+		Purpose:   auth.TokenPurposeMFAChallenge,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.AuthResponse{
+		ExpiresAt:      expiresAt,
+		MFARequired:    true,
+		ChallengeToken: challenge,
+		User:           u,
+	}, nil
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens.
+// O token apresentado é revogado; reapresentar um token já rotacionado
+// indica roubo e revoga toda a família.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*auth.AuthResponse, error) {
+	current, err := s.refreshRepo.GetByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil {
+		if err := s.refreshRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	u, err := s.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	nextID := uuid.New()
+	revoked, err := s.refreshRepo.Revoke(ctx, current.ID, &nextID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// Outra requisição rotacionou o mesmo token ao mesmo tempo
+		if err := s.refreshRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, auth.ErrInvalidRefreshToken
+	}
+
+	return s.issueSessionWithID(ctx, u, current.FamilyID, nextID)
+}
+
+// Logout revoga a sessão (família) do refresh token informado
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.refreshRepo.GetByHash(ctx, hashToken(refreshToken))
 	if err != nil {
-		return nil, errors.New("credenciais inválidas")
+		if errors.Is(err, auth.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
 	}
+	return s.refreshRepo.RevokeFamily(ctx, current.FamilyID)
+}
+
+// LogoutAll revoga todos os refresh tokens do usuário em todos os dispositivos
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return s.refreshRepo.RevokeAllForUser(ctx, userID)
+}
+
+func (s *AuthService) issueSession(ctx context.Context, u *user.User, familyID uuid.UUID) (*auth.AuthResponse, error) {
+	return s.issueSessionWithID(ctx, u, familyID, uuid.New())
+}
+
+func (s *AuthService) issueSessionWithID(ctx context.Context, u *user.User, familyID, refreshID uuid.UUID) (*auth.AuthResponse, error) {
+	now := time.Now()
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	// Gerar token de acesso assinado
+	expiresAt := now.Add(s.settings.AccessTokenTTL)
+	token, err := s.tokens.Generate(&auth.Claims{
+		UserID:    u.ID,
+		Role:      u.Role,
+		Purpose:   auth.TokenPurposeAccess,
+		ExpiresAt: expiresAt,
+	})
 	if err != nil {
-		return nil, errors.New("credenciais inválidas")
+		return nil, err
 	}
 
-	// Gerar token simples (em produção usar JWT)
-	token := uuid.New().String()
+	// Gerar refresh token opaco, persistido apenas como hash
+	rawRefresh, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	refresh := &auth.RefreshToken{
+		ID:        refreshID,
+		UserID:    u.ID,
+		FamilyID:  familyID,
+		TokenHash: refreshHash,
+		ExpiresAt: now.Add(s.settings.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+	if err := s.refreshRepo.Create(ctx, refresh); err != nil {
+		return nil, err
+	}
 
 	return &auth.AuthResponse{
-		Token: token,
-		User:  user,
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     rawRefresh,
+		RefreshExpiresAt: refresh.ExpiresAt,
+		User:             u,
 	}, nil
 }
 
 func (s *AuthService) ValidateToken(ctx context.Context, token string) (*user.User, error) {
-	// Implementação simples - em produção validar JWT
-	return nil, errors.New("token inválido")
+	_, u, err := s.validateAccessToken(ctx, token)
+	return u, err
+}
+
+// Authenticate valida o token de acesso e resolve a identidade do chamador,
+// incluindo o administrador quando o token é de personificação
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*auth.Identity, error) {
+	claims, u, err := s.validateAccessToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.ResolveIdentity(ctx, u)
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	if claims.ImpersonatorID != nil {
+		// O administrador precisa continuar existindo e com a role admin
+		admin, err := s.userRepo.GetByID(ctx, *claims.ImpersonatorID)
+		if err != nil || admin.Role != "admin" {
+			return nil, auth.ErrInvalidToken
+		}
+		identity.Impersonator = admin
+	}
+
+	return identity, nil
+}
+
+func (s *AuthService) validateAccessToken(ctx context.Context, token string) (*auth.Claims, *user.User, error) {
+	claims, err := s.tokens.Parse(token)
+	if err != nil || claims.Purpose != auth.TokenPurposeAccess {
+		return nil, nil, auth.ErrInvalidToken
+	}
+
+	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, nil, auth.ErrInvalidToken
+	}
+
+	// Token emitido antes de uma troca de role não é mais válido
+	if u.Role != claims.Role {
+		return nil, nil, auth.ErrInvalidToken
+	}
+
+	return claims, u, nil
+}
+
+// ResolveIdentity carrega o perfil de empresa ou profissional associado ao usuário
+func (s *AuthService) ResolveIdentity(ctx context.Context, u *user.User) (*auth.Identity, error) {
+	identity := &auth.Identity{User: u}
+
+	switch u.Role {
+	case "company":
+		company, err := s.companyRepo.GetCompanyByUserID(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		identity.Company = company
+
+	case "professional":
+		professional, err := s.profRepo.GetProfessionalByUserID(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		identity.Professional = professional
+
+		if professional.CompanyID != nil && !u.TOTPEnabled {
+			company, err := s.companyRepo.GetCompanyByID(ctx, *professional.CompanyID)
+			if err != nil {
+				return nil, err
+			}
+			identity.MFAEnrollmentRequired = company.RequireProfessionalMFA
+		}
+	}
+
+	return identity, nil
 }
@@ -0,0 +1,236 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+	"youmeet/internal/infra/config"
+	"youmeet/internal/infra/token"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type authFixture struct {
+	db       repositories.DBClient
+	service  *services.AuthService
+	tokens   auth.TokenManager
+	userRepo *repositories.UserRepository
+	guard    *services.LoginGuard
+	mfa      *services.MFAService
+}
+
+// defaultLoginGuard são limites folgados o bastante para não interferir nos testes
+var defaultLoginGuard = services.LoginGuardSettings{
+	MaxFailuresPerEmail: 5,
+	MaxFailuresPerIP:    20,
+	FreeAttempts:        3,
+	BaseDelay:           time.Second,
+	MaxDelay:            time.Minute,
+	LockoutDuration:     15 * time.Minute,
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	return newAuthFixtureWithGuard(t, defaultLoginGuard)
+}
+
+func newAuthFixtureWithGuard(t *testing.T, guardSettings services.LoginGuardSettings) *authFixture {
+	t.Helper()
+	db := newTestDB(t)
+
+	tokens, err := token.NewJWTManager(config.JWTConfig{
+		Algorithm: "HS256",
+		Secret:    "segredo-de-teste-com-mais-de-32-caracteres",
+		Issuer:    "youmeet-test",
+		AccessTTL: 15 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	loginGuard := services.NewLoginGuard(repositories.NewLoginThrottleRepository(db), repositories.NewAuditRepository(db), guardSettings)
+	companyRepo := repositories.NewCompanyRepository(db)
+	mfaService := services.NewMFAService(userRepo, companyRepo, repositories.NewRecoveryCodeRepository(db), "youmeet-test")
+	authService := services.NewAuthService(userRepo, companyRepo, repositories.NewProfessionalRepository(db), repositories.NewRefreshTokenRepository(db), tokens, loginGuard, mfaService, services.AuthSettings{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	})
+	return &authFixture{db: db, service: authService, tokens: tokens, userRepo: userRepo, guard: loginGuard, mfa: mfaService}
+}
+
+// login cadastra um usuário com a role informada e o autentica
+func (f *authFixture) login(t *testing.T, email, role string) *auth.AuthResponse {
+	t.Helper()
+	ctx := context.Background()
+	hash, err := bcrypt.GenerateFromPassword([]byte("senha-segura-123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	u := &user.User{ID: uuid.New(), Name: "Teste", Email: email, PasswordHash: string(hash), Role: role, CreatedAt: time.Now()}
+	if err := f.userRepo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	resp, err := f.service.Login(ctx, &auth.AuthRequest{Email: email, Password: "senha-segura-123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return resp
+}
+
+func TestValidateTokenReturnsTokenOwner(t *testing.T) {
+	f := newAuthFixture(t)
+	resp := f.login(t, "ana@example.com", "client")
+
+	u, err := f.service.ValidateToken(context.Background(), resp.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if u.ID != resp.User.ID {
+		t.Fatalf("usuário = %s, esperado %s", u.ID, resp.User.ID)
+	}
+}
+
+func TestValidateTokenRejectsStaleOrUnknownSubject(t *testing.T) {
+	f := newAuthFixture(t)
+	resp := f.login(t, "ana@example.com", "client")
+
+	// Token assinado com uma role que o usuário não tem mais
+	staleRole, err := f.tokens.Generate(&auth.Claims{UserID: resp.User.ID, Role: "company", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	unknownUser, err := f.tokens.Generate(&auth.Claims{UserID: uuid.New(), Role: "client", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	for name, signed := range map[string]string{"role alterada": staleRole, "usuário inexistente": unknownUser, "malformado": "abc"} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := f.service.ValidateToken(context.Background(), signed); !errors.Is(err, auth.ErrInvalidToken) {
+				t.Fatalf("erro = %v, esperado %v", err, auth.ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	first := f.login(t, "ana@example.com", "client")
+
+	second, err := f.service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == "" {
+		t.Fatalf("a renovação não emitiu um novo par de tokens: %+v", second)
+	}
+	if _, err := f.service.Refresh(ctx, second.RefreshToken); err != nil {
+		t.Fatalf("Refresh com o token rotacionado: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	first := f.login(t, "ana@example.com", "client")
+	other := f.login(t, "bruno@example.com", "client")
+
+	second, err := f.service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// Reapresentar o token já rotacionado indica roubo: a família inteira é revogada
+	if _, err := f.service.Refresh(ctx, first.RefreshToken); !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Fatalf("reuso: erro = %v, esperado %v", err, auth.ErrInvalidRefreshToken)
+	}
+	if _, err := f.service.Refresh(ctx, second.RefreshToken); !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Fatalf("token da família revogada: erro = %v, esperado %v", err, auth.ErrInvalidRefreshToken)
+	}
+
+	// Sessões de outros usuários não são afetadas
+	if _, err := f.service.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Fatalf("sessão de outro usuário: %v", err)
+	}
+}
+
+func TestLogoutRevokesSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	resp := f.login(t, "ana@example.com", "client")
+
+	if err := f.service.Logout(ctx, resp.RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := f.service.Refresh(ctx, resp.RefreshToken); !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Fatalf("token após logout: erro = %v, esperado %v", err, auth.ErrInvalidRefreshToken)
+	}
+
+	// LogoutAll encerra as sessões de todos os dispositivos do usuário
+	phone, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	laptop, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := f.service.LogoutAll(ctx, resp.User.ID); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+	for _, session := range []*auth.AuthResponse{phone, laptop} {
+		if _, err := f.service.Refresh(ctx, session.RefreshToken); !errors.Is(err, auth.ErrInvalidRefreshToken) {
+			t.Fatalf("token após LogoutAll: erro = %v, esperado %v", err, auth.ErrInvalidRefreshToken)
+		}
+	}
+}
+
+func TestRegisterDoesNotRevealExistingEmail(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	req := &auth.RegisterRequest{Name: "Ana", Email: "ana@example.com", Password: "senha-segura-123", Role: "client"}
+
+	if _, err := f.service.Register(ctx, req); err != nil {
+		t.Fatalf("primeiro cadastro: %v", err)
+	}
+	_, err := f.service.Register(ctx, req)
+	if !errors.Is(err, auth.ErrRegistrationFailed) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrRegistrationFailed)
+	}
+}
+
+// failingCompanyRepository recusa a criação de empresas
+type failingCompanyRepository struct {
+	user.CompanyRepository
+}
+
+func (failingCompanyRepository) CreateCompany(ctx context.Context, company *user.Company) error {
+	return errors.New("falha ao gravar a empresa")
+}
+
+func TestRegisterRemovesUserWhenProfileFails(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	authService := services.NewAuthService(f.userRepo, failingCompanyRepository{}, repositories.NewProfessionalRepository(f.db), repositories.NewRefreshTokenRepository(f.db), f.tokens, f.guard, f.mfa, services.AuthSettings{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+
+	_, err := authService.Register(ctx, &auth.RegisterRequest{Name: "Clínica", Email: "clinica@example.com", Password: "senha-segura-123", Role: "company"})
+	if err == nil {
+		t.Fatal("o cadastro foi aceito sem o perfil da empresa")
+	}
+	if _, err := f.userRepo.GetByEmail(ctx, "clinica@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("o usuário sem perfil foi mantido: %v", err)
+	}
+}
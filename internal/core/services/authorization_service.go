@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// Policy decide se a identidade pode acessar o recurso identificado por resourceID.
+// Retorna auth.ErrForbidden quando o acesso é negado.
+type Policy func(ctx context.Context, identity *auth.Identity, resourceID uuid.UUID) error
+
+type AuthorizationService struct {
+	appointmentRepo appointment.Repository
+	holdRepo        appointment.HoldRepository
+	waitlistRepo    appointment.WaitlistRepository
+	companyRepo     user.CompanyRepository
+	profRepo        user.ProfessionalRepository
+	userRepo        user.UserRepository
+}
+
+func NewAuthorizationService(appointmentRepo appointment.Repository, holdRepo appointment.HoldRepository, waitlistRepo appointment.WaitlistRepository, companyRepo user.CompanyRepository, profRepo user.ProfessionalRepository, userRepo user.UserRepository) *AuthorizationService {
+	return &AuthorizationService{
+		appointmentRepo: appointmentRepo,
+		holdRepo:        holdRepo,
+		waitlistRepo:    waitlistRepo,
+		companyRepo:     companyRepo,
+		profRepo:        profRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// RequireRole permite acesso apenas às roles informadas
+func RequireRole(roles ...string) Policy {
+	return func(ctx context.Context, identity *auth.Identity, _ uuid.UUID) error {
+		for _, role := range roles {
+			if identity.User.Role == role {
+				return nil
+			}
+		}
+		return auth.ErrForbidden
+	}
+}
+
+// BookAppointment permite agendar a clientes e a chaves de API, que agendam em nome da empresa
+func BookAppointment(ctx context.Context, identity *auth.Identity, _ uuid.UUID) error {
+	if identity.APIKey != nil {
+		return nil
+	}
+	return RequireRole("client")(ctx, identity, uuid.Nil)
+}
+
+// BookingClient resolve o cliente de um agendamento com o profissional. Usuários agendam para si mesmos;
+// chaves de API informam clientID, que precisa ser de um usuário com a role client, e só agendam com
+// profissionais da própria empresa.
+func (s *AuthorizationService) BookingClient(ctx context.Context, identity *auth.Identity, clientID *uuid.UUID, professionalID uuid.UUID) (uuid.UUID, error) {
+	if identity.APIKey == nil {
+		return identity.User.ID, nil
+	}
+	if clientID == nil {
+		return uuid.Nil, auth.ErrClientRequired
+	}
+
+	professional, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	// Profissionais de outras empresas não são revelados
+	if err := s.ownsProfessional(identity, professional); err != nil {
+		return uuid.Nil, user.ErrProfessionalNotFound
+	}
+
+	client, err := s.userRepo.GetByID(ctx, *clientID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if client.Role != "client" {
+		return uuid.Nil, auth.ErrInvalidClient
+	}
+	return client.ID, nil
+}
+
+// ViewCompany permite a qualquer usuário autenticado ler os dados públicos da empresa, como o calendário
+// de feriados; empresas inexistentes recebem ErrCompanyNotFound
+func (s *AuthorizationService) ViewCompany(ctx context.Context, identity *auth.Identity, companyID uuid.UUID) error {
+	_, err := s.companyRepo.GetCompanyByID(ctx, companyID)
+	return err
+}
+
+// ManageCompany permite acesso apenas ao dono da empresa
+func (s *AuthorizationService) ManageCompany(ctx context.Context, identity *auth.Identity, companyID uuid.UUID) error {
+	if identity.Company != nil && identity.Company.ID == companyID {
+		return nil
+	}
+	return auth.ErrForbidden
+}
+
+// ManageProfessional permite acesso à empresa dona do profissional.
+// Profissionais autônomos gerenciam o próprio perfil.
+func (s *AuthorizationService) ManageProfessional(ctx context.Context, identity *auth.Identity, professionalID uuid.UUID) error {
+	professional, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return err
+	}
+
+	if professional.CompanyID == nil {
+		return s.isProfessional(identity, professional)
+	}
+	return s.ownsProfessional(identity, professional)
+}
+
+// ViewProfessional permite a qualquer usuário autenticado ler os dados públicos do profissional, como a
+// política de cancelamento que vale para ele; profissionais inexistentes recebem ErrProfessionalNotFound
+func (s *AuthorizationService) ViewProfessional(ctx context.Context, identity *auth.Identity, professionalID uuid.UUID) error {
+	_, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	return err
+}
+
+// AccessProfessional permite acesso ao próprio profissional e à empresa dona dele
+func (s *AuthorizationService) AccessProfessional(ctx context.Context, identity *auth.Identity, professionalID uuid.UUID) error {
+	professional, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return err
+	}
+
+	if s.isProfessional(identity, professional) == nil {
+		return nil
+	}
+	return s.ownsProfessional(identity, professional)
+}
+
+// ManageSchedule permite ao próprio profissional e à empresa dona dele gerenciar a agenda
+func (s *AuthorizationService) ManageSchedule(ctx context.Context, identity *auth.Identity, professionalID uuid.UUID) error {
+	return s.AccessProfessional(ctx, identity, professionalID)
+}
+
+// ReadAppointment permite acesso ao cliente, ao profissional atribuído e à empresa do profissional
+func (s *AuthorizationService) ReadAppointment(ctx context.Context, identity *auth.Identity, appointmentID uuid.UUID) error {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+
+	if appt.ClientID == identity.User.ID {
+		return nil
+	}
+
+	err = s.AccessProfessional(ctx, identity, appt.ProfessionalID)
+	if errors.Is(err, user.ErrProfessionalNotFound) {
+		return auth.ErrForbidden
+	}
+	return err
+}
+
+// ManageAppointment permite ao profissional atribuído e à empresa dele conduzir o atendimento
+func (s *AuthorizationService) ManageAppointment(ctx context.Context, identity *auth.Identity, appointmentID uuid.UUID) error {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+
+	err = s.AccessProfessional(ctx, identity, appt.ProfessionalID)
+	if errors.Is(err, user.ErrProfessionalNotFound) {
+		return auth.ErrForbidden
+	}
+	return err
+}
+
+// RescheduleTarget autoriza a troca de profissional em uma remarcação. O novo profissional precisa ser da
+// mesma empresa do atual; profissionais e empresas ainda precisam ter acesso a ele, enquanto o cliente
+// pode escolher qualquer colega da empresa que o atende.
+func (s *AuthorizationService) RescheduleTarget(ctx context.Context, identity *auth.Identity, appointmentID, professionalID uuid.UUID) error {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+	if appt.ProfessionalID == professionalID {
+		return nil
+	}
+
+	current, err := s.profRepo.GetProfessionalByID(ctx, appt.ProfessionalID)
+	if err != nil {
+		return err
+	}
+	target, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return err
+	}
+	// Profissionais de outras empresas não são revelados
+	if current.CompanyID == nil || target.CompanyID == nil || *current.CompanyID != *target.CompanyID {
+		return user.ErrProfessionalNotFound
+	}
+
+	if appt.ClientID == identity.User.ID {
+		return nil
+	}
+	return s.AccessProfessional(ctx, identity, target.ID)
+}
+
+// OwnAppointment permite acesso apenas ao cliente do agendamento
+func (s *AuthorizationService) OwnAppointment(ctx context.Context, identity *auth.Identity, appointmentID uuid.UUID) error {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+
+	if appt.ClientID == identity.User.ID {
+		return nil
+	}
+	return auth.ErrForbidden
+}
+
+// ReadSeries permite acesso ao cliente da série, ao profissional dela e à empresa do profissional
+func (s *AuthorizationService) ReadSeries(ctx context.Context, identity *auth.Identity, seriesID uuid.UUID) error {
+	series, err := s.appointmentRepo.GetSeriesByID(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+
+	if series.ClientID == identity.User.ID {
+		return nil
+	}
+
+	err = s.AccessProfessional(ctx, identity, series.ProfessionalID)
+	if errors.Is(err, user.ErrProfessionalNotFound) {
+		return auth.ErrForbidden
+	}
+	return err
+}
+
+// OwnWaitlistEntry permite acesso apenas ao cliente da entrada da lista de espera
+func (s *AuthorizationService) OwnWaitlistEntry(ctx context.Context, identity *auth.Identity, entryID uuid.UUID) error {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	if entry.ClientID == identity.User.ID {
+		return nil
+	}
+	return auth.ErrForbidden
+}
+
+// OwnHold permite acesso ao cliente da reserva temporária e às chaves de API da empresa do profissional,
+// que reservam em nome dos clientes
+func (s *AuthorizationService) OwnHold(ctx context.Context, identity *auth.Identity, holdID uuid.UUID) error {
+	hold, err := s.holdRepo.GetHoldByID(ctx, holdID)
+	if err != nil {
+		return err
+	}
+
+	if identity.APIKey == nil {
+		if hold.ClientID == identity.User.ID {
+			return nil
+		}
+		return auth.ErrForbidden
+	}
+
+	professional, err := s.profRepo.GetProfessionalByID(ctx, hold.ProfessionalID)
+	if errors.Is(err, user.ErrProfessionalNotFound) {
+		return auth.ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	return s.ownsProfessional(identity, professional)
+}
+
+func (s *AuthorizationService) isProfessional(identity *auth.Identity, professional *user.Professional) error {
+	if identity.Professional != nil && identity.Professional.ID == professional.ID {
+		return nil
+	}
+	return auth.ErrForbidden
+}
+
+func (s *AuthorizationService) ownsProfessional(identity *auth.Identity, professional *user.Professional) error {
+	if identity.Company != nil && professional.CompanyID != nil && *professional.CompanyID == identity.Company.ID {
+		return nil
+	}
+	return auth.ErrForbidden
+}
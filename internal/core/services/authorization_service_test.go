@@ -0,0 +1,211 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+// authzFixture reúne uma empresa com dois profissionais, um profissional autônomo e um agendamento
+// de client com o primeiro profissional da empresa
+type authzFixture struct {
+	authz        *services.AuthorizationService
+	userRepo     *repositories.UserRepository
+	company      *auth.Identity
+	professional *auth.Identity
+	freelancer   *auth.Identity
+	colleague    *user.Professional
+	client       *auth.Identity
+	stranger     *auth.Identity
+	appointment  *appointment.Appointment
+}
+
+func newAuthzFixture(t *testing.T) *authzFixture {
+	t.Helper()
+	ctx := context.Background()
+	db := newTestDB(t)
+	appointmentRepo := repositories.NewAppointmentRepository(db)
+	companyRepo := repositories.NewCompanyRepository(db)
+	profRepo := repositories.NewProfessionalRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+
+	company := &user.Company{ID: uuid.New(), UserID: uuid.New(), Name: "Clínica"}
+	if err := companyRepo.CreateCompany(ctx, company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	employee := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Bruno", CompanyID: &company.ID}
+	colleague := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Diego", CompanyID: &company.ID}
+	freelancer := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Carla"}
+	for _, p := range []*user.Professional{employee, colleague, freelancer} {
+		if err := profRepo.CreateProfessional(ctx, p); err != nil {
+			t.Fatalf("CreateProfessional: %v", err)
+		}
+	}
+
+	client := &user.User{ID: uuid.New(), Name: "Ana", Email: "ana@example.com", Role: "client"}
+	if err := userRepo.Create(ctx, client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	start := time.Now().Add(24 * time.Hour)
+	appt := &appointment.Appointment{ID: uuid.New(), ClientID: client.ID, ProfessionalID: employee.ID, ServiceID: uuid.New(), StartTime: start, EndTime: start.Add(time.Hour), Status: "scheduled"}
+	if err := appointmentRepo.CreateAppointment(ctx, appt); err != nil {
+		t.Fatalf("CreateAppointment: %v", err)
+	}
+
+	return &authzFixture{
+		authz:        services.NewAuthorizationService(appointmentRepo, repositories.NewHoldRepository(db), repositories.NewWaitlistRepository(db), companyRepo, profRepo, userRepo),
+		userRepo:     userRepo,
+		company:      &auth.Identity{User: &user.User{ID: company.UserID, Role: "company"}, Company: company},
+		professional: &auth.Identity{User: &user.User{ID: employee.UserID, Role: "professional"}, Professional: employee},
+		freelancer:   &auth.Identity{User: &user.User{ID: freelancer.UserID, Role: "professional"}, Professional: freelancer},
+		colleague:    colleague,
+		client:       &auth.Identity{User: client},
+		stranger:     &auth.Identity{User: &user.User{ID: uuid.New(), Role: "client"}},
+		appointment:  appt,
+	}
+}
+
+func TestReadAppointmentPolicy(t *testing.T) {
+	f := newAuthzFixture(t)
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		want     error
+	}{
+		{name: "cliente do agendamento", identity: f.client},
+		{name: "profissional atribuído", identity: f.professional},
+		{name: "empresa do profissional", identity: f.company},
+		{name: "outro profissional", identity: f.freelancer, want: auth.ErrForbidden},
+		{name: "outro cliente", identity: f.stranger, want: auth.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.authz.ReadAppointment(context.Background(), tt.identity, f.appointment.ID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := f.authz.ReadAppointment(context.Background(), f.client, uuid.New()); !errors.Is(err, appointment.ErrNotFound) {
+		t.Fatalf("agendamento inexistente: erro = %v, esperado %v", err, appointment.ErrNotFound)
+	}
+}
+
+func TestManageProfessionalPolicy(t *testing.T) {
+	f := newAuthzFixture(t)
+	ctx := context.Background()
+
+	// O profissional de uma empresa é gerenciado por ela, não por ele mesmo
+	if err := f.authz.ManageProfessional(ctx, f.company, f.professional.Professional.ID); err != nil {
+		t.Fatalf("empresa gerenciando o próprio profissional: %v", err)
+	}
+	if err := f.authz.ManageProfessional(ctx, f.professional, f.professional.Professional.ID); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("profissional de empresa gerenciando o perfil: erro = %v, esperado %v", err, auth.ErrForbidden)
+	}
+	// O autônomo gerencia o próprio perfil
+	if err := f.authz.ManageProfessional(ctx, f.freelancer, f.freelancer.Professional.ID); err != nil {
+		t.Fatalf("autônomo gerenciando o próprio perfil: %v", err)
+	}
+	if err := f.authz.ManageProfessional(ctx, f.company, f.freelancer.Professional.ID); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("empresa gerenciando um autônomo: erro = %v, esperado %v", err, auth.ErrForbidden)
+	}
+}
+
+func TestViewCompanyPolicy(t *testing.T) {
+	f := newAuthzFixture(t)
+	ctx := context.Background()
+
+	if err := f.authz.ViewCompany(ctx, f.stranger, f.company.Company.ID); err != nil {
+		t.Fatalf("qualquer usuário lendo a empresa: %v", err)
+	}
+	if err := f.authz.ViewCompany(ctx, f.stranger, uuid.New()); !errors.Is(err, user.ErrCompanyNotFound) {
+		t.Fatalf("empresa inexistente: erro = %v, esperado %v", err, user.ErrCompanyNotFound)
+	}
+}
+
+func TestRescheduleTargetPolicy(t *testing.T) {
+	f := newAuthzFixture(t)
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		target   uuid.UUID
+		want     error
+	}{
+		{name: "mesmo profissional", identity: f.professional, target: f.appointment.ProfessionalID},
+		{name: "cliente para colega da empresa", identity: f.client, target: f.colleague.ID},
+		{name: "empresa para o próprio profissional", identity: f.company, target: f.colleague.ID},
+		{name: "profissional para colega", identity: f.professional, target: f.colleague.ID, want: auth.ErrForbidden},
+		{name: "cliente para autônomo", identity: f.client, target: f.freelancer.Professional.ID, want: user.ErrProfessionalNotFound},
+		{name: "profissional inexistente", identity: f.company, target: uuid.New(), want: user.ErrProfessionalNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.authz.RescheduleTarget(context.Background(), tt.identity, f.appointment.ID, tt.target)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireRolePolicy(t *testing.T) {
+	policy := services.RequireRole("company", "professional")
+
+	if err := policy(context.Background(), &auth.Identity{User: &user.User{Role: "professional"}}, uuid.Nil); err != nil {
+		t.Fatalf("role permitida: %v", err)
+	}
+	if err := policy(context.Background(), &auth.Identity{User: &user.User{Role: "client"}}, uuid.Nil); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("role não permitida: erro = %v, esperado %v", err, auth.ErrForbidden)
+	}
+}
+
+func TestBookingClient(t *testing.T) {
+	f := newAuthzFixture(t)
+	ctx := context.Background()
+	apiKey := &auth.Identity{User: f.company.User, Company: f.company.Company, APIKey: &auth.APIKey{CompanyID: f.company.Company.ID}}
+
+	// Usuários agendam sempre para si mesmos, mesmo informando outro client_id
+	id, err := f.authz.BookingClient(ctx, f.stranger, &f.appointment.ClientID, f.appointment.ProfessionalID)
+	if err != nil || id != f.stranger.User.ID {
+		t.Fatalf("BookingClient = %s, %v, esperado o próprio usuário", id, err)
+	}
+
+	id, err = f.authz.BookingClient(ctx, apiKey, &f.appointment.ClientID, f.appointment.ProfessionalID)
+	if err != nil || id != f.appointment.ClientID {
+		t.Fatalf("BookingClient = %s, %v, esperado o client_id informado", id, err)
+	}
+
+	if _, err := f.authz.BookingClient(ctx, apiKey, nil, f.appointment.ProfessionalID); !errors.Is(err, auth.ErrClientRequired) {
+		t.Fatalf("erro = %v, esperado ErrClientRequired", err)
+	}
+
+	unknown := uuid.New()
+	if _, err := f.authz.BookingClient(ctx, apiKey, &unknown, f.appointment.ProfessionalID); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("erro = %v, esperado ErrNotFound", err)
+	}
+
+	provider := &user.User{ID: uuid.New(), Name: "Bruno", Email: "bruno@example.com", Role: "professional"}
+	if err := f.userRepo.Create(ctx, provider); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.authz.BookingClient(ctx, apiKey, &provider.ID, f.appointment.ProfessionalID); !errors.Is(err, auth.ErrInvalidClient) {
+		t.Fatalf("erro = %v, esperado ErrInvalidClient", err)
+	}
+
+	// Profissionais de outras empresas não são revelados à chave
+	if _, err := f.authz.BookingClient(ctx, apiKey, &f.appointment.ClientID, f.freelancer.Professional.ID); !errors.Is(err, user.ErrProfessionalNotFound) {
+		t.Fatalf("erro = %v, esperado ErrProfessionalNotFound", err)
+	}
+}
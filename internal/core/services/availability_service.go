@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// AvailabilityService gerencia as janelas semanais de atendimento dos profissionais
+type AvailabilityService struct {
+	availabilityRepo appointment.AvailabilityRepository
+	exceptionRepo    appointment.ExceptionRepository
+	appointmentRepo  appointment.Repository
+	companyRepo      user.CompanyRepository
+	profRepo         user.ProfessionalRepository
+	tx               appointment.TransactionManager
+}
+
+func NewAvailabilityService(availabilityRepo appointment.AvailabilityRepository, exceptionRepo appointment.ExceptionRepository, appointmentRepo appointment.Repository, companyRepo user.CompanyRepository, profRepo user.ProfessionalRepository, tx appointment.TransactionManager) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		exceptionRepo:    exceptionRepo,
+		appointmentRepo:  appointmentRepo,
+		companyRepo:      companyRepo,
+		profRepo:         profRepo,
+		tx:               tx,
+	}
+}
+
+// List retorna as janelas do profissional ordenadas de domingo a sábado
+func (s *AvailabilityService) List(ctx context.Context, professionalID uuid.UUID) ([]*appointment.Availability, error) {
+	availabilities, err := s.availabilityRepo.GetByProfessional(ctx, professionalID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(availabilities, func(i, j int) bool {
+		di, _ := availabilities[i].Weekday()
+		dj, _ := availabilities[j].Weekday()
+		return di < dj
+	})
+	return availabilities, nil
+}
+
+func (s *AvailabilityService) Create(ctx context.Context, professionalID uuid.UUID, availability *appointment.Availability) (*appointment.Availability, error) {
+	availability.ID = uuid.New()
+	availability.ProfessionalID = professionalID
+	err := s.save(ctx, availability, func(ctx context.Context) error {
+		return s.availabilityRepo.CreateAvailability(ctx, availability)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// Update substitui o dia, os horários e as pausas de uma janela existente
+func (s *AvailabilityService) Update(ctx context.Context, professionalID, availabilityID uuid.UUID, changes *appointment.Availability) (*appointment.Availability, error) {
+	existing, err := s.get(ctx, professionalID, availabilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes.ID = existing.ID
+	changes.ProfessionalID = existing.ProfessionalID
+	changes.CreatedAt = existing.CreatedAt
+	err = s.save(ctx, changes, func(ctx context.Context) error {
+		return s.availabilityRepo.UpdateAvailability(ctx, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (s *AvailabilityService) Delete(ctx context.Context, professionalID, availabilityID uuid.UUID) error {
+	if _, err := s.get(ctx, professionalID, availabilityID); err != nil {
+		return err
+	}
+	return s.availabilityRepo.DeleteAvailability(ctx, availabilityID)
+}
+
+// get carrega a janela garantindo que ela pertence ao profissional da rota
+func (s *AvailabilityService) get(ctx context.Context, professionalID, availabilityID uuid.UUID) (*appointment.Availability, error) {
+	availability, err := s.availabilityRepo.GetAvailabilityByID(ctx, availabilityID)
+	if err != nil {
+		return nil, err
+	}
+	if availability.ProfessionalID != professionalID {
+		return nil, appointment.ErrAvailabilityNotFound
+	}
+	return availability, nil
+}
+
+// save verifica a janela e chama persist na mesma transação em que garante que ela não se sobrepõe às outras
+// do mesmo dia. O bloqueio da agenda impede que gravações simultâneas passem juntas pela verificação.
+func (s *AvailabilityService) save(ctx context.Context, availability *appointment.Availability, persist func(ctx context.Context) error) error {
+	if err := availability.Validate(); err != nil {
+		return err
+	}
+	for i := range availability.Breaks {
+		availability.Breaks[i].ID = uuid.New()
+		availability.Breaks[i].AvailabilityID = availability.ID
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.appointmentRepo.LockProfessionalSchedule(ctx, availability.ProfessionalID); err != nil {
+			return err
+		}
+
+		existing, err := s.availabilityRepo.GetByProfessional(ctx, availability.ProfessionalID)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.ID == availability.ID {
+				continue
+			}
+			// Janelas antigas podem ter sido gravadas sem normalização
+			if other.Validate() != nil {
+				continue
+			}
+			if availability.Overlaps(other) {
+				return appointment.ErrAvailabilityOverlap
+			}
+		}
+		return persist(ctx)
+	})
+}
+
+// ListExceptions retorna as exceções do profissional que se sobrepõem a [from, to)
+func (s *AvailabilityService) ListExceptions(ctx context.Context, professionalID uuid.UUID, from, to time.Time) ([]*appointment.AvailabilityException, error) {
+	return s.exceptionRepo.ListExceptions(ctx, []uuid.UUID{professionalID}, from, to)
+}
+
+func (s *AvailabilityService) CreateException(ctx context.Context, professionalID uuid.UUID, exception *appointment.AvailabilityException) (*appointment.AvailabilityException, error) {
+	if err := exception.Validate(); err != nil {
+		return nil, err
+	}
+	exception.ID = uuid.New()
+	exception.ProfessionalID = professionalID
+
+	if err := s.exceptionRepo.CreateException(ctx, exception); err != nil {
+		return nil, err
+	}
+	return exception, nil
+}
+
+func (s *AvailabilityService) DeleteException(ctx context.Context, professionalID, exceptionID uuid.UUID) error {
+	exception, err := s.exceptionRepo.GetExceptionByID(ctx, exceptionID)
+	if err != nil {
+		return err
+	}
+	if exception.ProfessionalID != professionalID {
+		return appointment.ErrExceptionNotFound
+	}
+	return s.exceptionRepo.DeleteException(ctx, exceptionID)
+}
+
+func (s *AvailabilityService) ListHolidays(ctx context.Context, companyID uuid.UUID) ([]*appointment.Holiday, error) {
+	return s.exceptionRepo.ListCompanyHolidays(ctx, companyID)
+}
+
+// CreateHoliday adiciona um dia sem atendimento para todos os profissionais da empresa
+func (s *AvailabilityService) CreateHoliday(ctx context.Context, companyID uuid.UUID, holiday *appointment.Holiday) (*appointment.Holiday, error) {
+	if err := holiday.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.exceptionRepo.ListHolidays(ctx, []uuid.UUID{companyID}, holiday.Date, holiday.Date)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, appointment.ErrHolidayAlreadyExists
+	}
+
+	holiday.ID = uuid.New()
+	holiday.CompanyID = companyID
+	if err := s.exceptionRepo.CreateHoliday(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *AvailabilityService) DeleteHoliday(ctx context.Context, companyID, holidayID uuid.UUID) error {
+	holiday, err := s.exceptionRepo.GetHolidayByID(ctx, holidayID)
+	if err != nil {
+		return err
+	}
+	if holiday.CompanyID != companyID {
+		return appointment.ErrHolidayNotFound
+	}
+	return s.exceptionRepo.DeleteHoliday(ctx, holidayID)
+}
+
+// GetProfessionalTimeZone retorna o fuso definido pelo profissional e o fuso efetivamente usado na agenda
+func (s *AvailabilityService) GetProfessionalTimeZone(ctx context.Context, professionalID uuid.UUID) (string, *time.Location, error) {
+	professionals, err := s.profRepo.ListProfessionalsByIDs(ctx, []uuid.UUID{professionalID})
+	if err != nil {
+		return "", nil, err
+	}
+	if len(professionals) == 0 {
+		return "", nil, user.ErrProfessionalNotFound
+	}
+	return professionals[0].TimeZone, professionals[0].Location(), nil
+}
+
+// SetProfessionalTimeZone define o fuso das janelas do profissional. Vazio volta a usar o fuso da empresa.
+func (s *AvailabilityService) SetProfessionalTimeZone(ctx context.Context, professionalID uuid.UUID, timeZone string) (string, error) {
+	timeZone, err := canonicalTimeZone(timeZone)
+	if err != nil {
+		return "", err
+	}
+	return timeZone, s.profRepo.SetTimeZone(ctx, professionalID, timeZone)
+}
+
+// SetCompanyTimeZone define o fuso dos feriados da empresa e dos profissionais sem fuso próprio
+func (s *AvailabilityService) SetCompanyTimeZone(ctx context.Context, companyID uuid.UUID, timeZone string) (string, error) {
+	timeZone, err := canonicalTimeZone(timeZone)
+	if err != nil {
+		return "", err
+	}
+	return timeZone, s.companyRepo.SetTimeZone(ctx, companyID, timeZone)
+}
+
+func canonicalTimeZone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	loc, err := user.LoadLocation(name)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
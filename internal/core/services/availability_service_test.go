@@ -0,0 +1,375 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+type availabilityFixture struct {
+	availability  *services.AvailabilityService
+	professionals user.ProfessionalRepository
+}
+
+func newAvailabilityFixture(t *testing.T) *availabilityFixture {
+	t.Helper()
+	db := newTestDB(t)
+	profRepo := repositories.NewProfessionalRepository(db)
+	return &availabilityFixture{
+		availability:  services.NewAvailabilityService(repositories.NewAvailabilityRepository(db), repositories.NewExceptionRepository(db), repositories.NewAppointmentRepository(db), repositories.NewCompanyRepository(db), profRepo, repositories.NewTransactionManager(db)),
+		professionals: profRepo,
+	}
+}
+
+// newProfessional cadastra um profissional sem empresa e retorna o ID
+func (f *availabilityFixture) newProfessional(t *testing.T) uuid.UUID {
+	t.Helper()
+	professional := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Dr. Bruno"}
+	if err := f.professionals.CreateProfessional(context.Background(), professional); err != nil {
+		t.Fatalf("CreateProfessional: %v", err)
+	}
+	return professional.ID
+}
+
+func TestAvailabilityValidation(t *testing.T) {
+	f := newAvailabilityFixture(t)
+	professionalID := f.newProfessional(t)
+
+	tests := []struct {
+		name         string
+		availability appointment.Availability
+	}{
+		{"dia inexistente", appointment.Availability{DayOfWeek: "funday", StartTime: "09:00", EndTime: "12:00"}},
+		{"horário fora do formato", appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "9h", EndTime: "12:00"}},
+		{"início depois do fim", appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "12:00", EndTime: "09:00"}},
+		{"pausa fora da janela", appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "09:00", EndTime: "12:00", Breaks: []appointment.AvailabilityBreak{
+			{StartTime: "11:30", EndTime: "12:30"},
+		}}},
+		{"pausas sobrepostas", appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "08:00", EndTime: "18:00", Breaks: []appointment.AvailabilityBreak{
+			{StartTime: "12:00", EndTime: "13:00"},
+			{StartTime: "12:30", EndTime: "13:30"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.availability.Create(context.Background(), professionalID, &tt.availability)
+			if !errors.Is(err, appointment.ErrInvalidAvailability) {
+				t.Fatalf("erro = %v, esperado %v", err, appointment.ErrInvalidAvailability)
+			}
+		})
+	}
+}
+
+func TestAvailabilityNormalizesAndRejectsOverlap(t *testing.T) {
+	f := newAvailabilityFixture(t)
+	ctx := context.Background()
+	professionalID := f.newProfessional(t)
+
+	morning, err := f.availability.Create(ctx, professionalID, &appointment.Availability{DayOfWeek: "Monday", StartTime: "9:00", EndTime: "12:00"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if morning.DayOfWeek != appointment.Monday || morning.StartTime != "09:00" {
+		t.Fatalf("janela = %s %s, esperado monday 09:00", morning.DayOfWeek, morning.StartTime)
+	}
+
+	if _, err := f.availability.Create(ctx, professionalID, &appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "11:00", EndTime: "14:00"}); !errors.Is(err, appointment.ErrAvailabilityOverlap) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrAvailabilityOverlap)
+	}
+	// Outro dia, outro profissional e a própria janela não contam como sobreposição
+	if _, err := f.availability.Create(ctx, professionalID, &appointment.Availability{DayOfWeek: appointment.Tuesday, StartTime: "11:00", EndTime: "14:00"}); err != nil {
+		t.Fatalf("Create em outro dia: %v", err)
+	}
+	if _, err := f.availability.Create(ctx, f.newProfessional(t), &appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "11:00", EndTime: "14:00"}); err != nil {
+		t.Fatalf("Create de outro profissional: %v", err)
+	}
+	if _, err := f.availability.Update(ctx, professionalID, morning.ID, &appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "08:00", EndTime: "12:00"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+func TestAvailabilityUpdateReplacesBreaks(t *testing.T) {
+	f := newAvailabilityFixture(t)
+	ctx := context.Background()
+	professionalID := f.newProfessional(t)
+
+	created, err := f.availability.Create(ctx, professionalID, &appointment.Availability{
+		DayOfWeek: appointment.Monday,
+		StartTime: "08:00",
+		EndTime:   "18:00",
+		Breaks:    []appointment.AvailabilityBreak{{StartTime: "12:00", EndTime: "13:00"}, {StartTime: "15:00", EndTime: "15:15"}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	_, err = f.availability.Update(ctx, professionalID, created.ID, &appointment.Availability{
+		DayOfWeek: appointment.Monday,
+		StartTime: "08:00",
+		EndTime:   "18:00",
+		Breaks:    []appointment.AvailabilityBreak{{StartTime: "12:30", EndTime: "13:30"}},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	list, err := f.availability.List(ctx, professionalID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 || len(list[0].Breaks) != 1 || list[0].Breaks[0].StartTime != "12:30" {
+		t.Fatalf("janelas = %+v, esperado apenas a pausa das 12:30", list)
+	}
+}
+
+func TestAvailabilityBelongsToProfessional(t *testing.T) {
+	f := newAvailabilityFixture(t)
+	ctx := context.Background()
+	professionalID := f.newProfessional(t)
+
+	created, err := f.availability.Create(ctx, professionalID, &appointment.Availability{DayOfWeek: appointment.Monday, StartTime: "08:00", EndTime: "12:00"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := f.availability.Delete(ctx, uuid.New(), created.ID); !errors.Is(err, appointment.ErrAvailabilityNotFound) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrAvailabilityNotFound)
+	}
+	if err := f.availability.Delete(ctx, professionalID, created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := f.availability.Delete(ctx, professionalID, created.ID); !errors.Is(err, appointment.ErrAvailabilityNotFound) {
+		t.Fatalf("erro = %v, esperado %v para janela removida", err, appointment.ErrAvailabilityNotFound)
+	}
+}
+
+func TestConcurrentOverlappingAvailabilitiesAdmitOnlyOne(t *testing.T) {
+	f := newAvailabilityFixture(t)
+	professionalID := f.newProfessional(t)
+
+	starts := []string{"08:00", "08:30", "09:00", "09:30"}
+	errs := make([]error, len(starts))
+	ready := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, start := range starts {
+		wg.Add(1)
+		go func(i int, start string) {
+			defer wg.Done()
+			<-ready
+			_, errs[i] = f.availability.Create(context.Background(), professionalID, &appointment.Availability{DayOfWeek: appointment.Monday, StartTime: start, EndTime: "12:00"})
+		}(i, start)
+	}
+	close(ready)
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, appointment.ErrAvailabilityOverlap):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d janelas sobrepostas foram gravadas, esperado 1", created)
+	}
+}
+
+func TestExceptionAndHolidayValidation(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	start := timeAt(t, 9, 0)
+
+	exceptions := []*appointment.AvailabilityException{
+		{Kind: "vacation", StartTime: start, EndTime: start.Add(time.Hour)},
+		{Kind: appointment.ExceptionTimeOff, StartTime: start, EndTime: start},
+		{Kind: appointment.ExceptionExtraHours, StartTime: start, EndTime: start.Add(-time.Hour)},
+	}
+	for _, exception := range exceptions {
+		if _, err := f.schedules.CreateException(ctx, f.professional.ID, exception); !errors.Is(err, appointment.ErrInvalidException) {
+			t.Fatalf("exceção %+v: erro = %v, esperado %v", exception, err, appointment.ErrInvalidException)
+		}
+	}
+
+	holidays := []*appointment.Holiday{
+		{Date: "25/12/2030", Name: "Natal"},
+		{Date: "2030-12-25", Name: "  "},
+	}
+	for _, holiday := range holidays {
+		if _, err := f.schedules.CreateHoliday(ctx, *f.professional.CompanyID, holiday); !errors.Is(err, appointment.ErrInvalidHoliday) {
+			t.Fatalf("feriado %+v: erro = %v, esperado %v", holiday, err, appointment.ErrInvalidHoliday)
+		}
+	}
+}
+
+func TestTimeOffBlocksBookingAndSlots(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	timeOff, err := f.schedules.CreateException(ctx, f.professional.ID, &appointment.AvailabilityException{
+		Kind:      appointment.ExceptionTimeOff,
+		StartTime: timeAt(t, 9, 0),
+		EndTime:   timeAt(t, 12, 0),
+		Reason:    "consulta médica",
+	})
+	if err != nil {
+		t.Fatalf("CreateException: %v", err)
+	}
+
+	_, err = f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil)
+	if !errors.Is(err, appointment.ErrOutsideAvailability) || !strings.Contains(err.Error(), "consulta médica") {
+		t.Fatalf("erro = %v, esperado %v com o motivo da folga", err, appointment.ErrOutsideAvailability)
+	}
+
+	slots, err := f.newSlotService(0).FindSlots(ctx, svc.ID, nil, timeAt(t, 8, 0), timeAt(t, 13, 0))
+	if err != nil {
+		t.Fatalf("FindSlots: %v", err)
+	}
+	if len(slots) != 2 || !slots[0].StartTime.Equal(timeAt(t, 8, 0)) || !slots[1].StartTime.Equal(timeAt(t, 12, 0)) {
+		t.Fatalf("horários = %+v, esperado apenas 08:00 e 12:00", slots)
+	}
+
+	// Sem a folga o horário volta a ficar livre
+	if err := f.schedules.DeleteException(ctx, uuid.New(), timeOff.ID); !errors.Is(err, appointment.ErrExceptionNotFound) {
+		t.Fatalf("erro = %v, esperado %v para outro profissional", err, appointment.ErrExceptionNotFound)
+	}
+	if err := f.schedules.DeleteException(ctx, f.professional.ID, timeOff.ID); err != nil {
+		t.Fatalf("DeleteException: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+}
+
+func TestExtraHoursOpenBookingOutsideWindows(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	_, err := f.schedules.CreateException(ctx, f.professional.ID, &appointment.AvailabilityException{
+		Kind:      appointment.ExceptionExtraHours,
+		StartTime: timeAt(t, 19, 0),
+		EndTime:   timeAt(t, 21, 0),
+	})
+	if err != nil {
+		t.Fatalf("CreateException: %v", err)
+	}
+
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(19, 30), nil); err != nil {
+		t.Fatalf("BookAppointment no horário extra: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(20, 30), nil); !errors.Is(err, appointment.ErrOutsideAvailability) {
+		t.Fatalf("erro = %v, esperado %v além do horário extra", err, appointment.ErrOutsideAvailability)
+	}
+}
+
+func TestHolidayBlocksCompanyProfessionals(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	companyID := *f.professional.CompanyID
+
+	holiday, err := f.schedules.CreateHoliday(ctx, companyID, &appointment.Holiday{Date: appointment.DateOf(timeAt(t, 10, 0)), Name: "Aniversário da cidade"})
+	if err != nil {
+		t.Fatalf("CreateHoliday: %v", err)
+	}
+	if _, err := f.schedules.CreateHoliday(ctx, companyID, &appointment.Holiday{Date: holiday.Date, Name: "Repetido"}); !errors.Is(err, appointment.ErrHolidayAlreadyExists) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrHolidayAlreadyExists)
+	}
+
+	_, err = f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil)
+	if !errors.Is(err, appointment.ErrOutsideAvailability) || !strings.Contains(err.Error(), "Aniversário da cidade") {
+		t.Fatalf("erro = %v, esperado %v com o nome do feriado", err, appointment.ErrOutsideAvailability)
+	}
+	slots, err := f.newSlotService(0).FindSlots(ctx, svc.ID, nil, timeAt(t, 8, 0), timeAt(t, 18, 0))
+	if err != nil {
+		t.Fatalf("FindSlots: %v", err)
+	}
+	if len(slots) != 0 {
+		t.Fatalf("%d horários livres no feriado, esperado nenhum", len(slots))
+	}
+
+	if err := f.schedules.DeleteHoliday(ctx, uuid.New(), holiday.ID); !errors.Is(err, appointment.ErrHolidayNotFound) {
+		t.Fatalf("erro = %v, esperado %v para outra empresa", err, appointment.ErrHolidayNotFound)
+	}
+}
+
+func TestConcurrentDuplicateHolidaysAdmitOnlyOne(t *testing.T) {
+	f := newBookingFixture(t)
+	companyID := *f.professional.CompanyID
+
+	errs := make([]error, 4)
+	ready := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-ready
+			_, errs[i] = f.schedules.CreateHoliday(context.Background(), companyID, &appointment.Holiday{Date: "2030-12-25", Name: "Natal"})
+		}(i)
+	}
+	close(ready)
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, appointment.ErrHolidayAlreadyExists):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d feriados gravados na mesma data, esperado 1", created)
+	}
+}
+
+func TestBookingFollowsScheduleTimeZone(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	if _, err := f.schedules.SetCompanyTimeZone(ctx, *f.professional.CompanyID, "Marte/Olympus"); !errors.Is(err, user.ErrInvalidTimeZone) {
+		t.Fatalf("erro = %v, esperado %v", err, user.ErrInvalidTimeZone)
+	}
+
+	// Em São Paulo (UTC-3) a janela das 08:00 às 18:00 vai das 11:00 às 21:00 UTC
+	if _, err := f.schedules.SetCompanyTimeZone(ctx, *f.professional.CompanyID, "America/Sao_Paulo"); err != nil {
+		t.Fatalf("SetCompanyTimeZone: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(9, 0), nil); !errors.Is(err, appointment.ErrOutsideAvailability) {
+		t.Fatalf("erro = %v, esperado %v às 06:00 em São Paulo", err, appointment.ErrOutsideAvailability)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(20, 0), nil); err != nil {
+		t.Fatalf("BookAppointment às 17:00 em São Paulo: %v", err)
+	}
+
+	// O fuso do profissional tem prioridade sobre o da empresa; em Tóquio (UTC+9) 08:00 UTC são 17:00
+	if _, err := f.schedules.SetProfessionalTimeZone(ctx, f.professional.ID, "Asia/Tokyo"); err != nil {
+		t.Fatalf("SetProfessionalTimeZone: %v", err)
+	}
+	name, loc, err := f.schedules.GetProfessionalTimeZone(ctx, f.professional.ID)
+	if err != nil || name != "Asia/Tokyo" || loc.String() != "Asia/Tokyo" {
+		t.Fatalf("GetProfessionalTimeZone = %q, %v, %v, esperado Asia/Tokyo", name, loc, err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(8, 0), nil); err != nil {
+		t.Fatalf("BookAppointment às 17:00 em Tóquio: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); !errors.Is(err, appointment.ErrOutsideAvailability) {
+		t.Fatalf("erro = %v, esperado %v às 19:00 em Tóquio", err, appointment.ErrOutsideAvailability)
+	}
+}
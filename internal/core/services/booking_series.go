@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// BookSeries agenda em uma única transação as ocorrências da recorrência que começa em startTimeStr.
+// Em SeriesAllOrNothing qualquer ocorrência em conflito impede a série; em SeriesBookAvailable as
+// ocorrências livres são agendadas e as demais voltam em Conflicts. Se nenhuma ocorrência puder ser
+// agendada, retorna *appointment.SeriesConflictError.
+func (s *BookingService) BookSeries(ctx context.Context, serviceID, professionalID, clientID uuid.UUID, startTimeStr string, recurrence appointment.Recurrence, mode string) (*appointment.SeriesBooking, error) {
+	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	// Horários que já passaram não podem ser reservados
+	if err != nil || !startTime.After(time.Now()) {
+		return nil, appointment.ErrInvalidStartTime
+	}
+	switch mode {
+	case "":
+		mode = appointment.SeriesAllOrNothing
+	case appointment.SeriesAllOrNothing, appointment.SeriesBookAvailable:
+	default:
+		return nil, fmt.Errorf("%w: mode deve ser %q ou %q", appointment.ErrInvalidRecurrence, appointment.SeriesAllOrNothing, appointment.SeriesBookAvailable)
+	}
+
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(professionalID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+
+	// As ocorrências repetem o horário local do profissional, mesmo quando o horário de verão muda no meio da série
+	loc, err := s.schedules.location(ctx, professionalID)
+	if err != nil {
+		return nil, err
+	}
+	starts, err := recurrence.Occurrences(startTime, loc)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Duration(svc.Duration) * time.Minute
+	window := interval{start: starts[0], end: starts[len(starts)-1].Add(duration)}
+
+	schedules, err := s.schedules.load(ctx, []uuid.UUID{professionalID}, window.start, window.end)
+	if err != nil {
+		return nil, err
+	}
+	var free []interval
+	var conflicts []appointment.OccurrenceConflict
+	for _, start := range starts {
+		occurrence := interval{start: start, end: start.Add(duration)}
+		if err := schedules[professionalID].check(occurrence.start, occurrence.end); err != nil {
+			conflicts = append(conflicts, appointment.OccurrenceConflict{StartTime: occurrence.start, EndTime: occurrence.end, Reason: err.Error()})
+			continue
+		}
+		free = append(free, occurrence)
+	}
+
+	now := time.Now().UTC()
+	series := &appointment.Series{
+		ID:             uuid.New(),
+		ClientID:       clientID,
+		ProfessionalID: professionalID,
+		ServiceID:      serviceID,
+		Recurrence:     recurrence,
+		CreatedAt:      now,
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// O bloqueio impede que reservas concorrentes passem juntas pela verificação ou ocupem a mesma vaga
+		if err := s.appointmentRepo.LockProfessionalSchedule(ctx, professionalID); err != nil {
+			return err
+		}
+
+		// O buffer precisa ficar livre antes e depois de cada ocorrência
+		search := window.padded(s.settings.Buffer)
+		existing, err := listOccupants(ctx, s.appointmentRepo, s.holdRepo, []uuid.UUID{professionalID}, search.start, search.end)
+		if err != nil {
+			return err
+		}
+		var appts []*appointment.Appointment
+		for _, occurrence := range free {
+			if err := checkSeat(existing, svc, clientID, occurrence, s.settings.Buffer, nil); err != nil {
+				conflicts = append(conflicts, appointment.OccurrenceConflict{StartTime: occurrence.start, EndTime: occurrence.end, Reason: err.Error()})
+				continue
+			}
+			appts = append(appts, &appointment.Appointment{
+				ID:              uuid.New(),
+				ServiceID:       serviceID,
+				ProfessionalID:  professionalID,
+				ClientID:        clientID,
+				SeriesID:        &series.ID,
+				StartTime:       occurrence.start,
+				EndTime:         occurrence.end,
+				Status:          appointment.StatusPending,
+				StatusChangedAt: &now,
+				CreatedAt:       now,
+			})
+		}
+		sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].StartTime.Before(conflicts[j].StartTime) })
+		if len(appts) == 0 || (len(conflicts) > 0 && mode == appointment.SeriesAllOrNothing) {
+			return &appointment.SeriesConflictError{Conflicts: conflicts}
+		}
+
+		if err := s.appointmentRepo.CreateSeries(ctx, series); err != nil {
+			return err
+		}
+		for _, appt := range appts {
+			if err := s.appointmentRepo.CreateAppointment(ctx, appt); err != nil {
+				return err
+			}
+			err := s.appointmentRepo.AddStatusChange(ctx, &appointment.StatusChange{
+				ID:            uuid.New(),
+				AppointmentID: appt.ID,
+				ToStatus:      appt.Status,
+				ChangedBy:     clientID,
+				CreatedAt:     now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		series.Appointments = appts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &appointment.SeriesBooking{Series: series, Conflicts: conflicts}, nil
+}
+
+func (s *BookingService) GetSeries(ctx context.Context, id uuid.UUID) (*appointment.Series, error) {
+	return s.appointmentRepo.GetSeriesByID(ctx, id)
+}
+
+// loadOccurrences retorna os agendamentos da série de anchor afetados por uma alteração no escopo informado
+func loadOccurrences(ctx context.Context, appointmentRepo appointment.Repository, anchor *appointment.Appointment, scope string) ([]*appointment.Appointment, error) {
+	var series []*appointment.Appointment
+	if scope != appointment.ScopeThis && anchor.SeriesID != nil {
+		var err error
+		if series, err = appointmentRepo.ListBySeries(ctx, *anchor.SeriesID); err != nil {
+			return nil, err
+		}
+	}
+	return appointment.SelectOccurrences(anchor, series, scope, time.Now())
+}
+
+// shiftOccurrences calcula o novo período de cada agendamento quando anchor passa a começar em start:
+// os demais andam o mesmo número de dias no calendário de loc e passam ao horário local de start
+func shiftOccurrences(appts []*appointment.Appointment, anchor *appointment.Appointment, start time.Time, duration time.Duration, loc *time.Location) []interval {
+	from, to := anchor.StartTime.In(loc), start.In(loc)
+	days := int(dateOnly(to).Sub(dateOnly(from)).Hours() / 24)
+
+	moves := make([]interval, len(appts))
+	for i, a := range appts {
+		moved := start
+		if a.ID != anchor.ID {
+			year, month, day := a.StartTime.In(loc).Date()
+			moved = appointment.LocalTime(year, month, day+days, to.Hour(), to.Minute(), loc).UTC()
+		}
+		moves[i] = interval{start: moved, end: moved.Add(duration)}
+	}
+	return moves
+}
+
+// dateOnly retorna a data de t à meia-noite em UTC, para contar dias de calendário sem efeito do horário de verão
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// occurrenceError identifica a ocorrência que impediu uma alteração em várias ocorrências da série
+func occurrenceError(appts []*appointment.Appointment, i int, err error) error {
+	if len(appts) == 1 {
+		return err
+	}
+	return fmt.Errorf("ocorrência de %s: %w", appts[i].StartTime.UTC().Format(time.RFC3339), err)
+}
+
+// uniqueSorted remove repetições e ordena os IDs, definindo a ordem em que as agendas são bloqueadas
+func uniqueSorted(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var unique []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].String() < unique[j].String() })
+	return unique
+}
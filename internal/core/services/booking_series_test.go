@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// weeklyAt retorna o horário de slotAt deslocado em semanas, no formato aceito pelas requisições
+func weeklyAt(t *testing.T, week, hour int) string {
+	t.Helper()
+	return timeAt(t, hour, 0).AddDate(0, 0, 7*week).Format(time.RFC3339)
+}
+
+// bookWeekly agenda uma série semanal de count ocorrências às 10:00
+func (f *bookingFixture) bookWeekly(t *testing.T, svc *service.Service, clientID uuid.UUID, count int) *appointment.Series {
+	t.Helper()
+	booking, err := f.booking.BookSeries(context.Background(), svc.ID, f.professional.ID, clientID, slotAt(10, 0), appointment.Recurrence{Frequency: appointment.FrequencyWeekly, Count: &count}, "")
+	if err != nil {
+		t.Fatalf("BookSeries: %v", err)
+	}
+	if len(booking.Series.Appointments) != count {
+		t.Fatalf("%d ocorrências agendadas, esperado %d", len(booking.Series.Appointments), count)
+	}
+	return booking.Series
+}
+
+func TestBookSeriesModes(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	count := 3
+	recurrence := appointment.Recurrence{Frequency: appointment.FrequencyWeekly, Count: &count}
+
+	// A segunda semana já está ocupada
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), weeklyAt(t, 1, 10), nil); err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+
+	_, err := f.booking.BookSeries(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), recurrence, appointment.SeriesAllOrNothing)
+	var conflictErr *appointment.SeriesConflictError
+	if !errors.As(err, &conflictErr) || len(conflictErr.Conflicts) != 1 {
+		t.Fatalf("erro = %v, esperado SeriesConflictError com uma ocorrência", err)
+	}
+	if got := conflictErr.Conflicts[0].StartTime.Format(time.RFC3339); got != weeklyAt(t, 1, 10) {
+		t.Fatalf("conflito em %s, esperado %s", got, weeklyAt(t, 1, 10))
+	}
+
+	booking, err := f.booking.BookSeries(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), recurrence, appointment.SeriesBookAvailable)
+	if err != nil {
+		t.Fatalf("BookSeries: %v", err)
+	}
+	if len(booking.Series.Appointments) != 2 || len(booking.Conflicts) != 1 {
+		t.Fatalf("série = %d agendadas e %d conflitos, esperado 2 e 1", len(booking.Series.Appointments), len(booking.Conflicts))
+	}
+}
+
+func TestBookSeriesRejectsInvalidRequests(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+	count := 2
+	weekly := appointment.Recurrence{Frequency: appointment.FrequencyWeekly, Count: &count}
+
+	tests := []struct {
+		name       string
+		start      string
+		recurrence appointment.Recurrence
+		mode       string
+		want       error
+	}{
+		{"horário passado", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), weekly, "", appointment.ErrInvalidStartTime},
+		{"modo desconhecido", slotAt(10, 0), weekly, "some", appointment.ErrInvalidRecurrence},
+		{"recorrência sem fim", slotAt(10, 0), appointment.Recurrence{Frequency: appointment.FrequencyWeekly}, "", appointment.ErrInvalidRecurrence},
+		{"fora da disponibilidade", slotAt(19, 0), weekly, "", appointment.ErrSeriesConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.booking.BookSeries(context.Background(), svc.ID, f.professional.ID, uuid.New(), tt.start, tt.recurrence, tt.mode)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRescheduleFollowingMovesSeriesToNewProfessional(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	colleague := f.newColleague(t)
+	svc := &service.Service{ID: uuid.New(), Name: "Terapia", Duration: 60, ProfessionalIDs: service.UUIDList{f.professional.ID, colleague.ID}}
+	if err := f.services.CreateService(ctx, svc); err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	client := appointment.Actor{UserID: uuid.New()}
+	series := f.bookWeekly(t, svc, client.UserID, 3)
+
+	moved, err := f.booking.RescheduleOccurrences(ctx, series.Appointments[1].ID, weeklyAt(t, 1, 14), &colleague.ID, appointment.ScopeFollowing, client)
+	if err != nil {
+		t.Fatalf("RescheduleOccurrences: %v", err)
+	}
+	if len(moved) != 2 {
+		t.Fatalf("%d ocorrências remarcadas, esperado 2", len(moved))
+	}
+
+	stored, err := f.booking.GetSeries(ctx, series.ID)
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	if stored.ProfessionalID != colleague.ID {
+		t.Fatalf("série com o profissional %s, esperado o colega", stored.ProfessionalID)
+	}
+	for week, a := range stored.Appointments {
+		want, professionalID := weeklyAt(t, week, 14), colleague.ID
+		if week == 0 {
+			want, professionalID = slotAt(10, 0), f.professional.ID
+		}
+		if got := a.StartTime.UTC().Format(time.RFC3339); got != want || a.ProfessionalID != professionalID {
+			t.Fatalf("ocorrência %d = %s com %s, esperado %s com %s", week, got, a.ProfessionalID, want, professionalID)
+		}
+	}
+
+	if _, err := f.booking.RescheduleOccurrences(ctx, series.Appointments[0].ID, slotAt(11, 0), nil, "next", client); !errors.Is(err, appointment.ErrInvalidScope) {
+		t.Fatalf("erro = %v, esperado ErrInvalidScope", err)
+	}
+}
+
+func TestRescheduleAllIsAtomic(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+	series := f.bookWeekly(t, svc, client.UserID, 3)
+
+	// A última semana já está ocupada às 15:00: nenhuma ocorrência é movida
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), weeklyAt(t, 2, 15), nil); err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.booking.RescheduleOccurrences(ctx, series.Appointments[0].ID, slotAt(15, 0), nil, appointment.ScopeAll, client); !errors.Is(err, appointment.ErrConflict) {
+		t.Fatalf("erro = %v, esperado ErrConflict", err)
+	}
+
+	stored, err := f.booking.GetSeries(ctx, series.ID)
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	for week, a := range stored.Appointments {
+		if got := a.StartTime.UTC().Format(time.RFC3339); got != weeklyAt(t, week, 10) || a.RescheduleCount != 0 {
+			t.Fatalf("ocorrência %d = %s, esperado mantida em %s", week, got, weeklyAt(t, week, 10))
+		}
+	}
+}
+
+func TestCancelOccurrencesScopes(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+	series := f.bookWeekly(t, svc, client.UserID, 3)
+
+	cancelled, err := f.lifecycle.CancelOccurrences(ctx, series.Appointments[1].ID, client, "viagem", appointment.ScopeFollowing)
+	if err != nil {
+		t.Fatalf("CancelOccurrences: %v", err)
+	}
+	if len(cancelled) != 2 {
+		t.Fatalf("%d ocorrências canceladas, esperado 2", len(cancelled))
+	}
+
+	stored, err := f.booking.GetSeries(ctx, series.ID)
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	for week, a := range stored.Appointments {
+		want := appointment.StatusCancelledByClient
+		if week == 0 {
+			want = appointment.StatusPending
+		}
+		if a.Status != want {
+			t.Fatalf("ocorrência %d = %s, esperado %s", week, a.Status, want)
+		}
+	}
+
+	// Agendamentos avulsos não aceitam escopos de série
+	single, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(15, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.lifecycle.CancelOccurrences(ctx, single.ID, client, "", appointment.ScopeAll); !errors.Is(err, appointment.ErrNotInSeries) {
+		t.Fatalf("erro = %v, esperado ErrNotInSeries", err)
+	}
+}
@@ -2,40 +2,214 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 	"github.com/google/uuid"
 	"youmeet/internal/core/domain/appointment"
 	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
 )
 
+// BookingSettings define o intervalo livre exigido entre agendamentos do mesmo profissional e por quanto tempo
+// uma reserva temporária segura o horário
+type BookingSettings struct {
+	Buffer  time.Duration
+	HoldTTL time.Duration
+	// MaxAdvance limita com quanta antecedência um horário pode ser reservado temporariamente
+	MaxAdvance time.Duration
+	// MaxActiveHolds limita as reservas temporárias válidas ao mesmo tempo por cliente
+	MaxActiveHolds int
+}
+
 type BookingService struct {
 	appointmentRepo appointment.Repository
+	holdRepo        appointment.HoldRepository
+	waitlistRepo    appointment.WaitlistRepository
 	serviceRepo     service.Repository
+	schedules       *scheduleLoader
+	policies        *PolicyService
+	tx              appointment.TransactionManager
+	settings        BookingSettings
 }
 
-func NewBookingService(appointmentRepo appointment.Repository, serviceRepo service.Repository) *BookingService {
+func NewBookingService(appointmentRepo appointment.Repository, holdRepo appointment.HoldRepository, waitlistRepo appointment.WaitlistRepository, availabilityRepo appointment.AvailabilityRepository, exceptionRepo appointment.ExceptionRepository, profRepo user.ProfessionalRepository, serviceRepo service.Repository, policies *PolicyService, tx appointment.TransactionManager, settings BookingSettings) *BookingService {
 	return &BookingService{
 		appointmentRepo: appointmentRepo,
+		holdRepo:        holdRepo,
+		waitlistRepo:    waitlistRepo,
 		serviceRepo:     serviceRepo,
+		policies:        policies,
+		schedules: &scheduleLoader{
+			availabilityRepo: availabilityRepo,
+			exceptionRepo:    exceptionRepo,
+			profRepo:         profRepo,
+		},
+		tx:       tx,
+		settings: settings,
 	}
 }
 
-func (s *BookingService) BookAppointment(ctx context.Context, serviceID, clientID uuid.UUID, startTimeStr string) (*appointment.Appointment, error) {
+// BookAppointment agenda o serviço. Com holdID, converte a reserva temporária do cliente, que precisa
+// ser do mesmo serviço, profissional e horário.
+func (s *BookingService) BookAppointment(ctx context.Context, serviceID, professionalID, clientID uuid.UUID, startTimeStr string, holdID *uuid.UUID) (*appointment.Appointment, error) {
 	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	// Horários que já passaram não podem ser reservados
+	if err != nil || !startTime.After(time.Now()) {
+		return nil, appointment.ErrInvalidStartTime
+	}
+
+	if holdID != nil {
+		hold, err := s.clientHold(ctx, *holdID, clientID)
+		if err != nil {
+			return nil, err
+		}
+		if hold.ServiceID != serviceID || hold.ProfessionalID != professionalID || !hold.StartTime.Equal(startTime) {
+			return nil, appointment.ErrHoldMismatch
+		}
+		return s.convert(ctx, hold)
+	}
+
+	// A duração do serviço define o intervalo ocupado na agenda do profissional
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(professionalID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+
+	// Horários são gravados em UTC para que as comparações de sobreposição funcionem em qualquer banco
+	return s.book(ctx, svc, professionalID, clientID, startTime.UTC(), nil)
+}
+
+// PlaceHold reserva o horário para o cliente por HoldTTL. Enquanto a reserva vale, o horário (ou uma vaga,
+// em serviços em grupo) fica fora da busca de horários e recusado para os demais clientes.
+// O horário deve estar entre agora e MaxAdvance, e cada cliente mantém no máximo MaxActiveHolds reservas.
+func (s *BookingService) PlaceHold(ctx context.Context, serviceID, professionalID, clientID uuid.UUID, startTimeStr string) (*appointment.Hold, error) {
+	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	if err != nil {
+		return nil, appointment.ErrInvalidStartTime
+	}
+
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(professionalID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+
+	now := time.Now().UTC()
+	start := startTime.UTC()
+	if !start.After(now) || start.Sub(now) > s.settings.MaxAdvance {
+		return nil, appointment.ErrHoldOutOfRange
+	}
+	hold := &appointment.Hold{
+		ID:             uuid.New(),
+		ProfessionalID: professionalID,
+		ServiceID:      serviceID,
+		ClientID:       clientID,
+		StartTime:      start,
+		EndTime:        start.Add(time.Duration(svc.Duration) * time.Minute),
+		ExpiresAt:      now.Add(s.settings.HoldTTL),
+		CreatedAt:      now,
+	}
+
+	err = s.claim(ctx, svc, professionalID, clientID, interval{start: hold.StartTime, end: hold.EndTime}, nil, func(ctx context.Context) error {
+		active, err := s.holdRepo.ListClientHolds(ctx, clientID, now)
+		if err != nil {
+			return err
+		}
+		if len(active) >= s.settings.MaxActiveHolds {
+			return fmt.Errorf("%w: máximo de %d", appointment.ErrHoldLimitReached, s.settings.MaxActiveHolds)
+		}
+		return s.holdRepo.CreateHold(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (s *BookingService) GetHold(ctx context.Context, id uuid.UUID) (*appointment.Hold, error) {
+	return s.holdRepo.GetHoldByID(ctx, id)
+}
+
+// ReleaseHold encerra a reserva antes do prazo. Ela vence na hora e é apagada pela verificação periódica,
+// que também passa ao próximo da fila os horários oferecidos pela lista de espera.
+func (s *BookingService) ReleaseHold(ctx context.Context, id uuid.UUID) error {
+	return s.holdRepo.ExpireHold(ctx, id, time.Now())
+}
+
+// ConvertHold transforma a reserva temporária do cliente em agendamento. Se a reserva veio da lista
+// de espera, a entrada correspondente é marcada como agendada na mesma transação.
+func (s *BookingService) ConvertHold(ctx context.Context, holdID, clientID uuid.UUID) (*appointment.Appointment, error) {
+	hold, err := s.clientHold(ctx, holdID, clientID)
 	if err != nil {
 		return nil, err
 	}
+	return s.convert(ctx, hold)
+}
 
+// clientHold retorna a reserva ainda válida do cliente; reservas de outros clientes não são reveladas
+func (s *BookingService) clientHold(ctx context.Context, holdID, clientID uuid.UUID) (*appointment.Hold, error) {
+	hold, err := s.holdRepo.GetHoldByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if hold.ClientID != clientID {
+		return nil, appointment.ErrHoldNotFound
+	}
+	if hold.Expired(time.Now()) {
+		return nil, appointment.ErrHoldExpired
+	}
+	return hold, nil
+}
+
+func (s *BookingService) convert(ctx context.Context, hold *appointment.Hold) (*appointment.Appointment, error) {
+	svc, err := s.serviceRepo.GetServiceByID(ctx, hold.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(hold.ProfessionalID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+	return s.book(ctx, svc, hold.ProfessionalID, hold.ClientID, hold.StartTime.UTC(), hold)
+}
+
+// book agenda o serviço com o profissional a partir de start. Com hold, a reserva é consumida na mesma
+// transação em que o agendamento é criado.
+func (s *BookingService) book(ctx context.Context, svc *service.Service, professionalID, clientID uuid.UUID, start time.Time, hold *appointment.Hold) (*appointment.Appointment, error) {
+	now := time.Now().UTC()
 	appt := &appointment.Appointment{
-		ID:        uuid.New(),
-		ServiceID: serviceID,
-		ClientID:  clientID,
-		StartTime: startTime,
-		Status:    "scheduled",
-		CreatedAt: time.Now(),
+		ID:              uuid.New(),
+		ServiceID:       svc.ID,
+		ProfessionalID:  professionalID,
+		ClientID:        clientID,
+		StartTime:       start,
+		EndTime:         start.Add(time.Duration(svc.Duration) * time.Minute),
+		Status:          appointment.StatusPending,
+		StatusChangedAt: &now,
+		CreatedAt:       now,
 	}
 
-	err = s.appointmentRepo.CreateAppointment(ctx, appt)
+	err := s.claim(ctx, svc, professionalID, clientID, interval{start: appt.StartTime, end: appt.EndTime}, hold, func(ctx context.Context) error {
+		if err := s.appointmentRepo.CreateAppointment(ctx, appt); err != nil {
+			return err
+		}
+		err := s.appointmentRepo.AddStatusChange(ctx, &appointment.StatusChange{
+			ID:            uuid.New(),
+			AppointmentID: appt.ID,
+			ToStatus:      appt.Status,
+			ChangedBy:     clientID,
+			CreatedAt:     now,
+		})
+		if err != nil || hold == nil {
+			return err
+		}
+		return s.consumeHold(ctx, hold, appt)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -43,10 +217,264 @@ func (s *BookingService) BookAppointment(ctx context.Context, serviceID, clientI
 	return appt, nil
 }
 
+// claim exige que period esteja aberto na agenda do profissional e livre para o cliente e chama save na mesma
+// transação. O bloqueio da agenda impede que reservas concorrentes passem juntas pela verificação ou ocupem a
+// mesma vaga. Com hold, a reserva do próprio cliente não conta como conflito, mas precisa continuar válida.
+func (s *BookingService) claim(ctx context.Context, svc *service.Service, professionalID, clientID uuid.UUID, period interval, hold *appointment.Hold, save func(ctx context.Context) error) error {
+	if err := s.checkAvailability(ctx, professionalID, period.start, period.end); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// O bloqueio impede que duas reservas concorrentes passem juntas pela verificação ou ocupem a mesma vaga
+		if err := s.appointmentRepo.LockProfessionalSchedule(ctx, professionalID); err != nil {
+			return err
+		}
+
+		var skip map[uuid.UUID]bool
+		if hold != nil {
+			// A reserva pode ter expirado ou sido usada enquanto a agenda não estava bloqueada
+			current, err := s.holdRepo.GetHoldByID(ctx, hold.ID)
+			if errors.Is(err, appointment.ErrHoldNotFound) {
+				return appointment.ErrHoldExpired
+			}
+			if err != nil {
+				return err
+			}
+			if current.Expired(time.Now()) {
+				return appointment.ErrHoldExpired
+			}
+			skip = map[uuid.UUID]bool{hold.ID: true}
+		}
+
+		// O buffer precisa ficar livre antes e depois de cada agendamento
+		search := period.padded(s.settings.Buffer)
+		existing, err := listOccupants(ctx, s.appointmentRepo, s.holdRepo, []uuid.UUID{professionalID}, search.start, search.end)
+		if err != nil {
+			return err
+		}
+		if err := checkSeat(existing, svc, clientID, period, s.settings.Buffer, skip); err != nil {
+			return err
+		}
+		return save(ctx)
+	})
+}
+
+// consumeHold apaga a reserva convertida em appt e marca como agendada a entrada da lista de espera que a recebeu
+func (s *BookingService) consumeHold(ctx context.Context, hold *appointment.Hold, appt *appointment.Appointment) error {
+	if err := s.holdRepo.DeleteHold(ctx, hold.ID); err != nil {
+		return err
+	}
+	if hold.WaitlistEntryID == nil {
+		return nil
+	}
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, *hold.WaitlistEntryID)
+	if err != nil {
+		return err
+	}
+	entry.Status = appointment.WaitlistBooked
+	entry.AppointmentID = &appt.ID
+	return s.waitlistRepo.UpdateEntry(ctx, entry)
+}
+
+// Reschedule move o agendamento para um novo horário e/ou profissional em uma única transação, refazendo
+// as verificações de disponibilidade e conflito e registrando o horário anterior. startTimeStr vazio mantém
+// o horário e professionalID nulo mantém o profissional. Clientes estão sujeitos à política de cancelamento e remarcação.
+func (s *BookingService) Reschedule(ctx context.Context, appointmentID uuid.UUID, startTimeStr string, professionalID *uuid.UUID, actor appointment.Actor) (*appointment.Appointment, error) {
+	moved, err := s.RescheduleOccurrences(ctx, appointmentID, startTimeStr, professionalID, appointment.ScopeThis, actor)
+	if err != nil {
+		return nil, err
+	}
+	return moved[0], nil
+}
+
+// RescheduleOccurrences remarca o agendamento e, conforme o escopo, as ocorrências seguintes ou toda a série.
+// As demais ocorrências andam a mesma quantidade de dias que o agendamento e passam ao mesmo horário local.
+// Todas são movidas na mesma transação: se alguma não couber na agenda, nenhuma é alterada.
+func (s *BookingService) RescheduleOccurrences(ctx context.Context, appointmentID uuid.UUID, startTimeStr string, professionalID *uuid.UUID, scope string, actor appointment.Actor) ([]*appointment.Appointment, error) {
+	anchor, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReschedulable(anchor); err != nil {
+		return nil, err
+	}
+	appts, err := loadOccurrences(ctx, s.appointmentRepo, anchor, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	start := anchor.StartTime.UTC()
+	if startTimeStr != "" {
+		startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			return nil, appointment.ErrInvalidStartTime
+		}
+		start = startTime.UTC()
+	}
+	// Horários que já passaram não podem ser reservados
+	if !start.After(time.Now()) {
+		return nil, appointment.ErrInvalidStartTime
+	}
+	targetID := anchor.ProfessionalID
+	if professionalID != nil {
+		targetID = *professionalID
+	}
+	if start.Equal(anchor.StartTime) && targetID == anchor.ProfessionalID {
+		return nil, appointment.ErrNothingToReschedule
+	}
+
+	var policies map[uuid.UUID]*appointment.Policy
+	if !actor.Provider {
+		if policies, err = s.policies.effectiveByProfessional(ctx, appts); err != nil {
+			return nil, err
+		}
+	}
+
+	svc, err := s.serviceRepo.GetServiceByID(ctx, anchor.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(targetID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+	loc, err := s.schedules.location(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Duration(svc.Duration) * time.Minute
+	moves := shiftOccurrences(appts, anchor, start, duration, loc)
+	window := interval{start: moves[0].start, end: moves[len(moves)-1].end}
+
+	schedules, err := s.schedules.load(ctx, []uuid.UUID{targetID}, window.start, window.end)
+	if err != nil {
+		return nil, err
+	}
+	for i, move := range moves {
+		if err := schedules[targetID].check(move.start, move.end); err != nil {
+			return nil, occurrenceError(appts, i, err)
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Bloqueia as agendas sempre na mesma ordem para que remarcações cruzadas não travem entre si
+		locks := []uuid.UUID{targetID}
+		for _, a := range appts {
+			locks = append(locks, a.ProfessionalID)
+		}
+		for _, id := range uniqueSorted(locks) {
+			if err := s.appointmentRepo.LockProfessionalSchedule(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		// Relê os agendamentos dentro da transação para não perder remarcações ou cancelamentos concorrentes
+		moving := make(map[uuid.UUID]bool, len(appts))
+		for i, a := range appts {
+			current, err := s.appointmentRepo.GetAppointmentByID(ctx, a.ID)
+			if err != nil {
+				return err
+			}
+			if err := checkReschedulable(current); err != nil {
+				return occurrenceError(appts, i, err)
+			}
+			if current.RescheduleCount != a.RescheduleCount || current.ProfessionalID != a.ProfessionalID {
+				return occurrenceError(appts, i, fmt.Errorf("%w: o agendamento foi alterado por outra requisição", appointment.ErrInvalidTransition))
+			}
+			if policy := policies[current.ProfessionalID]; policy != nil {
+				if err := policy.CheckReschedule(current, time.Now()); err != nil {
+					return occurrenceError(appts, i, err)
+				}
+			}
+			appts[i] = current
+			moving[current.ID] = true
+		}
+
+		// O buffer precisa ficar livre antes e depois de cada ocorrência
+		search := window.padded(s.settings.Buffer)
+		existing, err := listOccupants(ctx, s.appointmentRepo, s.holdRepo, []uuid.UUID{targetID}, search.start, search.end)
+		if err != nil {
+			return err
+		}
+		for i, move := range moves {
+			if err := checkSeat(existing, svc, appts[i].ClientID, move, s.settings.Buffer, moving); err != nil {
+				return occurrenceError(appts, i, err)
+			}
+		}
+
+		now := time.Now().UTC()
+		changedProfessional := false
+		for i, current := range appts {
+			changedProfessional = changedProfessional || current.ProfessionalID != targetID
+			record := appointment.Reschedule{
+				ID:                 uuid.New(),
+				AppointmentID:      current.ID,
+				FromProfessionalID: current.ProfessionalID,
+				FromStartTime:      current.StartTime.UTC(),
+				FromEndTime:        current.EndTime.UTC(),
+				ToProfessionalID:   targetID,
+				ToStartTime:        moves[i].start,
+				ToEndTime:          moves[i].end,
+				ChangedBy:          actor.UserID,
+				CreatedAt:          now,
+			}
+			current.ProfessionalID = targetID
+			current.StartTime = moves[i].start
+			current.EndTime = moves[i].end
+			current.RescheduleCount++
+			if err := s.appointmentRepo.UpdateSchedule(ctx, current); err != nil {
+				return err
+			}
+			if err := s.appointmentRepo.AddReschedule(ctx, &record); err != nil {
+				return err
+			}
+			current.RescheduleHistory = append(current.RescheduleHistory, record)
+		}
+
+		// A partir das ocorrências movidas, a série passa a ser atendida pelo novo profissional
+		if scope != appointment.ScopeThis && anchor.SeriesID != nil && changedProfessional {
+			return s.appointmentRepo.SetSeriesProfessional(ctx, *anchor.SeriesID, targetID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appts, nil
+}
+
+// checkReschedulable aceita apenas agendamentos que ainda não começaram a ser atendidos nem foram encerrados
+func checkReschedulable(appt *appointment.Appointment) error {
+	switch appt.CurrentStatus() {
+	case appointment.StatusPending, appointment.StatusConfirmed:
+		return nil
+	}
+	return fmt.Errorf("%w: não é possível remarcar um agendamento %s", appointment.ErrInvalidTransition, appointment.StatusName(appt.Status))
+}
+
+// checkAvailability exige que [start, end) esteja aberto na agenda do profissional,
+// considerando janelas semanais, pausas, horários extras, folgas e feriados
+func (s *BookingService) checkAvailability(ctx context.Context, professionalID uuid.UUID, start, end time.Time) error {
+	schedules, err := s.schedules.load(ctx, []uuid.UUID{professionalID}, start, end)
+	if err != nil {
+		return err
+	}
+	return schedules[professionalID].check(start, end)
+}
+
+func (s *BookingService) GetAppointment(ctx context.Context, id uuid.UUID) (*appointment.Appointment, error) {
+	return s.appointmentRepo.GetAppointmentByID(ctx, id)
+}
+
 func (s *BookingService) GetAppointments(ctx context.Context, clientID uuid.UUID) ([]*appointment.Appointment, error) {
 	return s.appointmentRepo.ListAppointments(ctx, clientID)
 }
 
 func (s *BookingService) GetAppointmentsByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*appointment.Appointment, error) {
 	return s.appointmentRepo.ListByProfessional(ctx, professionalID)
+}
+
+func (s *BookingService) GetAppointmentsByCompany(ctx context.Context, companyID uuid.UUID) ([]*appointment.Appointment, error) {
+	return s.appointmentRepo.ListByCompany(ctx, companyID)
 }
\ No newline at end of file
@@ -0,0 +1,446 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+func TestConcurrentBookingsOfSameSlotAdmitOnlyOne(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	starts := make([]string, 8)
+	for i := range starts {
+		starts[i] = slotAt(10, 0)
+	}
+	errs := f.bookConcurrently(svc, starts)
+
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, appointment.ErrConflict):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Fatalf("%d reservas concorrentes do mesmo horário foram aceitas, esperado 1", booked)
+	}
+}
+
+func TestConcurrentOverlappingBookingsAdmitOnlyOne(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	// Horários diferentes, mas com uma hora de duração todos se sobrepõem entre si
+	errs := f.bookConcurrently(svc, []string{slotAt(10, 0), slotAt(10, 15), slotAt(10, 30), slotAt(10, 45)})
+
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, appointment.ErrConflict):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Fatalf("%d reservas sobrepostas foram aceitas, esperado 1", booked)
+	}
+
+	appts, err := f.appointments.ListByProfessional(context.Background(), f.professional.ID)
+	if err != nil {
+		t.Fatalf("ListByProfessional: %v", err)
+	}
+	if len(appts) != 1 {
+		t.Fatalf("%d agendamentos gravados, esperado 1", len(appts))
+	}
+}
+
+func TestBookingRejectsOverlapWithExistingAppointment(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	if errs := f.bookConcurrently(svc, []string{slotAt(14, 0)}); errs[0] != nil {
+		t.Fatalf("primeira reserva: %v", errs[0])
+	}
+	for _, start := range []string{slotAt(14, 0), slotAt(13, 30), slotAt(14, 59)} {
+		if errs := f.bookConcurrently(svc, []string{start}); !errors.Is(errs[0], appointment.ErrConflict) {
+			t.Fatalf("reserva em %s: erro = %v, esperado %v", start, errs[0], appointment.ErrConflict)
+		}
+	}
+	for _, start := range []string{slotAt(13, 0), slotAt(15, 0)} {
+		if errs := f.bookConcurrently(svc, []string{start}); errs[0] != nil {
+			t.Fatalf("reserva adjacente em %s: %v", start, errs[0])
+		}
+	}
+}
+
+func TestBookingKeepsBufferAroundAppointments(t *testing.T) {
+	f := newBookingFixtureWithSettings(t, services.BookingSettings{Buffer: 15 * time.Minute})
+	svc := f.newService(t, 1)
+
+	if errs := f.bookConcurrently(svc, []string{slotAt(10, 0)}); errs[0] != nil {
+		t.Fatalf("primeira reserva: %v", errs[0])
+	}
+	// Encostar no agendamento invade o buffer de um dos dois lados
+	for _, start := range []string{slotAt(9, 0), slotAt(11, 0), slotAt(11, 14)} {
+		if errs := f.bookConcurrently(svc, []string{start}); !errors.Is(errs[0], appointment.ErrConflict) {
+			t.Fatalf("reserva em %s: erro = %v, esperado %v", start, errs[0], appointment.ErrConflict)
+		}
+	}
+	for _, start := range []string{slotAt(8, 45), slotAt(11, 15)} {
+		if errs := f.bookConcurrently(svc, []string{start}); errs[0] != nil {
+			t.Fatalf("reserva fora do buffer em %s: %v", start, errs[0])
+		}
+	}
+}
+
+func TestGetAppointmentsByCompanyListsOnlyItsProfessionals(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	appointmentRepo := repositories.NewAppointmentRepository(db)
+	companyRepo := repositories.NewCompanyRepository(db)
+	profRepo := repositories.NewProfessionalRepository(db)
+	booking := services.NewBookingService(appointmentRepo, repositories.NewHoldRepository(db), repositories.NewWaitlistRepository(db), repositories.NewAvailabilityRepository(db), repositories.NewExceptionRepository(db), profRepo, repositories.NewServiceRepository(db), services.NewPolicyService(repositories.NewPolicyRepository(db), profRepo), repositories.NewTransactionManager(db), services.BookingSettings{})
+
+	company := &user.Company{ID: uuid.New(), UserID: uuid.New(), Name: "Clínica"}
+	if err := companyRepo.CreateCompany(ctx, company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	employee := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Bruno", CompanyID: &company.ID}
+	freelancer := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Carla"}
+	for _, p := range []*user.Professional{employee, freelancer} {
+		if err := profRepo.CreateProfessional(ctx, p); err != nil {
+			t.Fatalf("CreateProfessional: %v", err)
+		}
+	}
+
+	start := time.Now().Add(24 * time.Hour)
+	own := &appointment.Appointment{ID: uuid.New(), ClientID: uuid.New(), ProfessionalID: employee.ID, ServiceID: uuid.New(), StartTime: start, EndTime: start.Add(time.Hour), Status: "scheduled"}
+	other := &appointment.Appointment{ID: uuid.New(), ClientID: uuid.New(), ProfessionalID: freelancer.ID, ServiceID: uuid.New(), StartTime: start, EndTime: start.Add(time.Hour), Status: "scheduled"}
+	for _, appt := range []*appointment.Appointment{own, other} {
+		if err := appointmentRepo.CreateAppointment(ctx, appt); err != nil {
+			t.Fatalf("CreateAppointment: %v", err)
+		}
+	}
+
+	appts, err := booking.GetAppointmentsByCompany(ctx, company.ID)
+	if err != nil {
+		t.Fatalf("GetAppointmentsByCompany: %v", err)
+	}
+	if len(appts) != 1 || appts[0].ID != own.ID {
+		t.Fatalf("agendamentos = %+v, esperado apenas %s", appts, own.ID)
+	}
+}
+
+func TestBookingDerivesEndTimeFromService(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	appt, err := f.booking.BookAppointment(context.Background(), svc.ID, f.professional.ID, uuid.New(), slotAt(9, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if got := appt.EndTime.Sub(appt.StartTime); got != time.Duration(svc.Duration)*time.Minute {
+		t.Fatalf("duração = %s, esperado %d minutos", got, svc.Duration)
+	}
+}
+
+func TestBookingRejectsInvalidRequests(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		serviceID      uuid.UUID
+		professionalID uuid.UUID
+		start          string
+		want           error
+	}{
+		{"horário no passado", svc.ID, f.professional.ID, past, appointment.ErrInvalidStartTime},
+		{"horário agora", svc.ID, f.professional.ID, time.Now().UTC().Truncate(time.Second).Format(time.RFC3339), appointment.ErrInvalidStartTime},
+		{"horário malformado", svc.ID, f.professional.ID, "amanhã às 10h", appointment.ErrInvalidStartTime},
+		{"serviço inexistente", uuid.New(), f.professional.ID, slotAt(10, 0), service.ErrNotFound},
+		{"profissional fora do serviço", svc.ID, uuid.New(), slotAt(10, 0), service.ErrProfessionalNotOffering},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.booking.BookAppointment(context.Background(), tt.serviceID, tt.professionalID, uuid.New(), tt.start, nil)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookingRequiresAvailabilityWindow(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	for _, start := range []string{slotAt(7, 0), slotAt(7, 30), slotAt(17, 30), slotAt(18, 0)} {
+		if errs := f.bookConcurrently(svc, []string{start}); !errors.Is(errs[0], appointment.ErrOutsideAvailability) {
+			t.Fatalf("reserva em %s: erro = %v, esperado %v", start, errs[0], appointment.ErrOutsideAvailability)
+		}
+	}
+	// O intervalo pode encostar nas bordas da janela
+	for _, start := range []string{slotAt(8, 0), slotAt(17, 0)} {
+		if errs := f.bookConcurrently(svc, []string{start}); errs[0] != nil {
+			t.Fatalf("reserva em %s: %v", start, errs[0])
+		}
+	}
+}
+
+func TestBookingRejectsDayWithoutAvailability(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	// Um segundo profissional que só atende no dia seguinte ao do agendamento
+	professionalID := uuid.New()
+	tomorrow, _ := time.Parse(time.RFC3339, slotAt(10, 0))
+	availability := &appointment.Availability{
+		ID:             uuid.New(),
+		ProfessionalID: professionalID,
+		DayOfWeek:      appointment.DayOfWeek(strings.ToLower(tomorrow.AddDate(0, 0, 1).Weekday().String())),
+		StartTime:      "08:00",
+		EndTime:        "18:00",
+	}
+	if err := f.availability.CreateAvailability(ctx, availability); err != nil {
+		t.Fatalf("CreateAvailability: %v", err)
+	}
+	svc := &service.Service{ID: uuid.New(), Name: "Consulta", Duration: 60, Price: 100, ProfessionalIDs: service.UUIDList{professionalID}}
+	if err := f.services.CreateService(ctx, svc); err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+
+	_, err := f.booking.BookAppointment(ctx, svc.ID, professionalID, uuid.New(), slotAt(10, 0), nil)
+	if !errors.Is(err, appointment.ErrOutsideAvailability) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrOutsideAvailability)
+	}
+	if !strings.Contains(err.Error(), appointment.WeekdayName(tomorrow.Weekday())) {
+		t.Fatalf("erro = %q, esperado o dia da semana sem atendimento no motivo", err)
+	}
+}
+
+func TestBookingRespectsAvailabilityBreaks(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	// Almoço das 12:00 às 13:00 em todas as janelas do profissional
+	availabilities, err := f.availability.GetByProfessional(ctx, f.professional.ID)
+	if err != nil {
+		t.Fatalf("GetByProfessional: %v", err)
+	}
+	for _, a := range availabilities {
+		a.Breaks = []appointment.AvailabilityBreak{{ID: uuid.New(), AvailabilityID: a.ID, StartTime: "12:00", EndTime: "13:00"}}
+		if err := f.availability.UpdateAvailability(ctx, a); err != nil {
+			t.Fatalf("UpdateAvailability: %v", err)
+		}
+	}
+
+	for _, start := range []string{slotAt(11, 30), slotAt(12, 0), slotAt(12, 30)} {
+		if errs := f.bookConcurrently(svc, []string{start}); !errors.Is(errs[0], appointment.ErrOutsideAvailability) {
+			t.Fatalf("reserva em %s: erro = %v, esperado %v", start, errs[0], appointment.ErrOutsideAvailability)
+		}
+	}
+	for _, start := range []string{slotAt(11, 0), slotAt(13, 0)} {
+		if errs := f.bookConcurrently(svc, []string{start}); errs[0] != nil {
+			t.Fatalf("reserva em %s: %v", start, errs[0])
+		}
+	}
+}
+
+func TestRescheduleMovesToAnotherProfessional(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	colleague := f.newColleague(t)
+	client := appointment.Actor{UserID: uuid.New()}
+
+	// O colega não oferece o serviço
+	single := f.newService(t, 1)
+	appt, err := f.booking.BookAppointment(ctx, single.ID, f.professional.ID, client.UserID, slotAt(8, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.booking.Reschedule(ctx, appt.ID, "", &colleague.ID, client); !errors.Is(err, service.ErrProfessionalNotOffering) {
+		t.Fatalf("erro = %v, esperado ErrProfessionalNotOffering", err)
+	}
+
+	svc := &service.Service{ID: uuid.New(), Name: "Retorno", Duration: 60, ProfessionalIDs: service.UUIDList{f.professional.ID, colleague.ID}}
+	if err := f.services.CreateService(ctx, svc); err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	appt, err = f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+
+	// O horário das 14:00 já está ocupado na agenda do colega
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, colleague.ID, uuid.New(), slotAt(14, 0), nil); err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.booking.Reschedule(ctx, appt.ID, slotAt(14, 0), &colleague.ID, client); !errors.Is(err, appointment.ErrConflict) {
+		t.Fatalf("erro = %v, esperado ErrConflict", err)
+	}
+	if _, err := f.booking.Reschedule(ctx, appt.ID, "", &f.professional.ID, client); !errors.Is(err, appointment.ErrNothingToReschedule) {
+		t.Fatalf("erro = %v, esperado ErrNothingToReschedule", err)
+	}
+
+	moved, err := f.booking.Reschedule(ctx, appt.ID, slotAt(16, 0), &colleague.ID, client)
+	if err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+	stored, err := f.appointments.GetAppointmentByID(ctx, appt.ID)
+	if err != nil {
+		t.Fatalf("GetAppointmentByID: %v", err)
+	}
+	if stored.ProfessionalID != colleague.ID || !stored.StartTime.Equal(timeAt(t, 16, 0)) || stored.RescheduleCount != 1 {
+		t.Fatalf("agendamento = %+v, esperado com o colega às 16:00", stored)
+	}
+	if len(stored.RescheduleHistory) != 1 || len(moved.RescheduleHistory) != 1 {
+		t.Fatalf("histórico = %+v, esperado uma remarcação", stored.RescheduleHistory)
+	}
+	record := stored.RescheduleHistory[0]
+	if record.FromProfessionalID != f.professional.ID || !record.FromStartTime.Equal(timeAt(t, 10, 0)) || record.ToProfessionalID != colleague.ID || record.ChangedBy != client.UserID {
+		t.Fatalf("remarcação = %+v, esperado de Dra. Ana às 10:00 para o colega", record)
+	}
+
+	// O horário antigo fica livre para o profissional original
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); err != nil {
+		t.Fatalf("horário liberado recusado: %v", err)
+	}
+}
+
+func TestRescheduleRejectsPastAndUnavailableTimes(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		start string
+		want  error
+	}{
+		{"horário inválido", "amanhã", appointment.ErrInvalidStartTime},
+		{"horário passado", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), appointment.ErrInvalidStartTime},
+		{"fora da disponibilidade", slotAt(19, 0), appointment.ErrOutsideAvailability},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.booking.Reschedule(ctx, appt.ID, tt.start, nil, client); !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceHoldRejectsStartOutOfRange(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+
+	for _, start := range []string{
+		time.Now().UTC().Add(-time.Hour).Format(time.RFC3339),
+		time.Now().UTC().AddDate(0, 0, 90).Format(time.RFC3339),
+	} {
+		_, err := f.booking.PlaceHold(context.Background(), svc.ID, f.professional.ID, uuid.New(), start)
+		if !errors.Is(err, appointment.ErrHoldOutOfRange) {
+			t.Fatalf("reserva para %s: erro = %v, esperado %v", start, err, appointment.ErrHoldOutOfRange)
+		}
+	}
+}
+
+func TestPlaceHoldLimitsActiveHoldsPerClient(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+	clientID := uuid.New()
+
+	for _, hour := range []int{8, 10, 12} {
+		if _, err := f.booking.PlaceHold(context.Background(), svc.ID, f.professional.ID, clientID, slotAt(hour, 0)); err != nil {
+			t.Fatalf("reserva das %dh: %v", hour, err)
+		}
+	}
+	_, err := f.booking.PlaceHold(context.Background(), svc.ID, f.professional.ID, clientID, slotAt(14, 0))
+	if !errors.Is(err, appointment.ErrHoldLimitReached) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrHoldLimitReached)
+	}
+
+	// Outro cliente não é afetado pelo limite
+	if _, err := f.booking.PlaceHold(context.Background(), svc.ID, f.professional.ID, uuid.New(), slotAt(14, 0)); err != nil {
+		t.Fatalf("reserva de outro cliente: %v", err)
+	}
+}
+
+func TestBookingConvertsHold(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	clientID := uuid.New()
+
+	hold, err := f.booking.PlaceHold(ctx, svc.ID, f.professional.ID, clientID, slotAt(10, 0))
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	// Enquanto a reserva vale, o horário é recusado para os demais clientes
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); !errors.Is(err, appointment.ErrSlotHeld) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrSlotHeld)
+	}
+	// A reserva não é revelada a outros clientes nem convertida em outro horário
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), &hold.ID); !errors.Is(err, appointment.ErrHoldNotFound) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrHoldNotFound)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, clientID, slotAt(11, 0), &hold.ID); !errors.Is(err, appointment.ErrHoldMismatch) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrHoldMismatch)
+	}
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, clientID, slotAt(10, 0), &hold.ID)
+	if err != nil {
+		t.Fatalf("BookAppointment com a reserva: %v", err)
+	}
+	if appt.ClientID != clientID || !appt.StartTime.Equal(timeAt(t, 10, 0)) {
+		t.Fatalf("agendamento = %+v, esperado do dono da reserva às 10:00", appt)
+	}
+	if _, err := f.booking.GetHold(ctx, hold.ID); !errors.Is(err, appointment.ErrHoldNotFound) {
+		t.Fatalf("erro = %v, esperado a reserva consumida", err)
+	}
+}
+
+func TestReleasedHoldFreesSlot(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	clientID := uuid.New()
+
+	hold, err := f.booking.PlaceHold(ctx, svc.ID, f.professional.ID, clientID, slotAt(10, 0))
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	if err := f.booking.ReleaseHold(ctx, hold.ID); err != nil {
+		t.Fatalf("ReleaseHold: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, clientID, slotAt(10, 0), &hold.ID); !errors.Is(err, appointment.ErrHoldExpired) {
+		t.Fatalf("erro = %v, esperado %v", err, appointment.ErrHoldExpired)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); err != nil {
+		t.Fatalf("BookAppointment depois de liberada a reserva: %v", err)
+	}
+}
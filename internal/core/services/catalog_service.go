@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"youmeet/internal/core/domain/service"
+)
+
+type CatalogService struct {
+	serviceRepo service.Repository
+}
+
+func NewCatalogService(serviceRepo service.Repository) *CatalogService {
+	return &CatalogService{
+		serviceRepo: serviceRepo,
+	}
+}
+
+func (s *CatalogService) ListServices(ctx context.Context) ([]*service.Service, error) {
+	return s.serviceRepo.ListServices(ctx)
+}
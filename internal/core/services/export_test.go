@@ -0,0 +1,20 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// TOTPCodeAt expõe aos testes externos o código TOTP esperado para o instante informado
+func TOTPCodeAt(secret string, at time.Time) string {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		panic(err)
+	}
+	return totpCode(key, uint64(at.Unix()/totpPeriod))
+}
+
+// SetClock substitui o relógio do serviço, para que os testes avancem o tempo sem esperar
+func (s *IdempotencyService) SetClock(now func() time.Time) {
+	s.now = now
+}
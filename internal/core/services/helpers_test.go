@@ -0,0 +1,251 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/idempotency"
+	"youmeet/internal/core/domain/notification"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+	"youmeet/internal/infra/database"
+
+	"github.com/google/uuid"
+)
+
+// newTestDB abre um banco SQLite exclusivo do teste, com as mesmas tabelas da aplicação
+func newTestDB(t *testing.T) repositories.DBClient {
+	t.Helper()
+
+	db, err := database.NewSQLiteClient(filepath.Join(t.TempDir(), "youmeet.db"))
+	if err != nil {
+		t.Fatalf("falha ao abrir o banco: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&user.User{},
+		&user.Company{},
+		&user.Professional{},
+		&auth.RefreshToken{},
+		&auth.ActionToken{},
+		&auth.LoginThrottle{},
+		&auth.RecoveryCode{},
+		&auth.APIKey{},
+		&auth.UserIdentity{},
+		&auth.OAuthState{},
+		&audit.AuditLog{},
+		&appointment.Appointment{},
+		&appointment.StatusChange{},
+		&appointment.Reschedule{},
+		&appointment.Series{},
+		&appointment.Hold{},
+		&appointment.WaitlistEntry{},
+		&appointment.Availability{},
+		&appointment.AvailabilityBreak{},
+		&appointment.AvailabilityException{},
+		&appointment.Holiday{},
+		&appointment.Policy{},
+		&service.Service{},
+		&idempotency.Record{},
+	)
+	if err != nil {
+		t.Fatalf("falha ao migrar o banco: %v", err)
+	}
+	return db
+}
+
+// outbox guarda os emails enviados durante o teste
+type outbox struct {
+	mu     sync.Mutex
+	emails []*notification.Email
+}
+
+func (o *outbox) Send(ctx context.Context, email *notification.Email) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.emails = append(o.emails, email)
+	return nil
+}
+
+func (o *outbox) sent() []*notification.Email {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]*notification.Email(nil), o.emails...)
+}
+
+var linkToken = regexp.MustCompile(`[?&]token=([^\s&]+)`)
+
+// lastToken retorna o token do link do último email enviado
+func (o *outbox) lastToken(t *testing.T) string {
+	t.Helper()
+	emails := o.sent()
+	if len(emails) == 0 {
+		t.Fatal("nenhum email foi enviado")
+	}
+	match := linkToken.FindStringSubmatch(emails[len(emails)-1].Body)
+	if match == nil {
+		t.Fatalf("email sem link com token: %s", emails[len(emails)-1].Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("token inválido no link: %v", err)
+	}
+	return token
+}
+
+// bookingFixture reúne um profissional atendendo todos os dias das 08:00 às 18:00 (UTC), o serviço de agendamento
+// e a lista de espera, que envia as ofertas para outbox
+type bookingFixture struct {
+	booking       *services.BookingService
+	lifecycle     *services.LifecycleService
+	waitlists     *services.WaitlistService
+	outbox        *outbox
+	policies      *services.PolicyService
+	schedules     *services.AvailabilityService
+	services      service.Repository
+	appointments  appointment.Repository
+	holds         appointment.HoldRepository
+	availability  appointment.AvailabilityRepository
+	exceptions    appointment.ExceptionRepository
+	professionals user.ProfessionalRepository
+	users         user.UserRepository
+	tx            appointment.TransactionManager
+	professional  *user.Professional
+}
+
+func newBookingFixture(t *testing.T) *bookingFixture {
+	return newBookingFixtureWithSettings(t, services.BookingSettings{
+		HoldTTL:        10 * time.Minute,
+		MaxAdvance:     62 * 24 * time.Hour,
+		MaxActiveHolds: 3,
+	})
+}
+
+func newBookingFixtureWithSettings(t *testing.T, settings services.BookingSettings) *bookingFixture {
+	t.Helper()
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	companyRepo := repositories.NewCompanyRepository(db)
+	profRepo := repositories.NewProfessionalRepository(db)
+	serviceRepo := repositories.NewServiceRepository(db)
+	appointmentRepo := repositories.NewAppointmentRepository(db)
+	availabilityRepo := repositories.NewAvailabilityRepository(db)
+	exceptionRepo := repositories.NewExceptionRepository(db)
+	txManager := repositories.NewTransactionManager(db)
+
+	company := &user.Company{ID: uuid.New(), UserID: uuid.New(), Name: "Clínica"}
+	if err := companyRepo.CreateCompany(ctx, company); err != nil {
+		t.Fatalf("CreateCompany: %v", err)
+	}
+	professional := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Dra. Ana", CompanyID: &company.ID}
+	if err := profRepo.CreateProfessional(ctx, professional); err != nil {
+		t.Fatalf("CreateProfessional: %v", err)
+	}
+
+	availabilityService := services.NewAvailabilityService(availabilityRepo, exceptionRepo, appointmentRepo, companyRepo, profRepo, txManager)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		_, err := availabilityService.Create(ctx, professional.ID, &appointment.Availability{DayOfWeek: appointment.DayOfWeek(day.String()), StartTime: "08:00", EndTime: "18:00"})
+		if err != nil {
+			t.Fatalf("Create availability: %v", err)
+		}
+	}
+
+	holdRepo := repositories.NewHoldRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	policyService := services.NewPolicyService(repositories.NewPolicyRepository(db), profRepo)
+	bookingService := services.NewBookingService(appointmentRepo, holdRepo, waitlistRepo, availabilityRepo, exceptionRepo, profRepo, serviceRepo, policyService, txManager, settings)
+	sent := &outbox{}
+	waitlistService := services.NewWaitlistService(waitlistRepo, holdRepo, appointmentRepo, serviceRepo, userRepo, bookingService, sent, txManager, services.WaitlistSettings{
+		OfferTTL:    30 * time.Minute,
+		LinkBaseURL: "https://app.example.com",
+		Buffer:      settings.Buffer,
+	})
+
+	return &bookingFixture{
+		booking:       bookingService,
+		lifecycle:     services.NewLifecycleService(appointmentRepo, policyService, waitlistService, txManager),
+		waitlists:     waitlistService,
+		outbox:        sent,
+		policies:      policyService,
+		schedules:     availabilityService,
+		services:      serviceRepo,
+		appointments:  appointmentRepo,
+		holds:         holdRepo,
+		availability:  availabilityRepo,
+		exceptions:    exceptionRepo,
+		professionals: profRepo,
+		users:         userRepo,
+		tx:            txManager,
+		professional:  professional,
+	}
+}
+
+// newColleague cadastra outro profissional da mesma empresa, com a mesma agenda
+func (f *bookingFixture) newColleague(t *testing.T) *user.Professional {
+	t.Helper()
+	ctx := context.Background()
+	colleague := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Dr. Caio", CompanyID: f.professional.CompanyID}
+	if err := f.professionals.CreateProfessional(ctx, colleague); err != nil {
+		t.Fatalf("CreateProfessional: %v", err)
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		_, err := f.schedules.Create(ctx, colleague.ID, &appointment.Availability{DayOfWeek: appointment.DayOfWeek(day.String()), StartTime: "08:00", EndTime: "18:00"})
+		if err != nil {
+			t.Fatalf("Create availability: %v", err)
+		}
+	}
+	return colleague
+}
+
+// newService cadastra um serviço de uma hora do profissional com a capacidade informada
+func (f *bookingFixture) newService(t *testing.T, capacity int) *service.Service {
+	t.Helper()
+	svc := &service.Service{
+		ID:              uuid.New(),
+		Name:            "Consulta",
+		Duration:        60,
+		Price:           100,
+		Capacity:        capacity,
+		ProfessionalIDs: service.UUIDList{f.professional.ID},
+	}
+	if err := f.services.CreateService(context.Background(), svc); err != nil {
+		t.Fatalf("CreateService: %v", err)
+	}
+	return svc
+}
+
+// slotAt retorna o horário de amanhã às hour:minute (UTC) no formato aceito pela API
+func slotAt(hour, minute int) string {
+	day := time.Now().UTC().AddDate(0, 0, 1)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC).Format(time.RFC3339)
+}
+
+// bookConcurrently dispara as reservas ao mesmo tempo e retorna o erro de cada uma, na ordem de starts
+func (f *bookingFixture) bookConcurrently(svc *service.Service, starts []string) []error {
+	errs := make([]error, len(starts))
+	ready := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, start := range starts {
+		wg.Add(1)
+		go func(i int, start string) {
+			defer wg.Done()
+			<-ready
+			_, errs[i] = f.booking.BookAppointment(context.Background(), svc.ID, f.professional.ID, uuid.New(), start, nil)
+		}(i, start)
+	}
+	close(ready)
+	wg.Wait()
+	return errs
+}
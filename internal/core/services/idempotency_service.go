@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"youmeet/internal/core/domain/idempotency"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencySettings define por quanto tempo uma chave de idempotência devolve a mesma resposta e por
+// quanto tempo uma requisição em andamento detém a chave
+type IdempotencySettings struct {
+	KeyTTL  time.Duration
+	LockTTL time.Duration
+}
+
+// IdempotencyService registra as requisições feitas com Idempotency-Key e as respostas devolvidas às repetições
+type IdempotencyService struct {
+	repo     idempotency.Repository
+	settings IdempotencySettings
+	now      func() time.Time
+}
+
+func NewIdempotencyService(repo idempotency.Repository, settings IdempotencySettings) *IdempotencyService {
+	return &IdempotencyService{
+		repo:     repo,
+		settings: settings,
+		now:      time.Now,
+	}
+}
+
+// Begin registra a requisição com a chave. Quando ela é a primeira e deve ser processada, o registro retornado
+// está em andamento e seu LeaseToken é exigido por Complete e Abandon; quando é uma repetição idêntica de uma
+// requisição já concluída, o registro traz a resposta gravada. A mesma chave com outro fingerprint retorna
+// ErrKeyReused; uma repetição antes do fim da primeira, ErrInProgress. Se a primeira não respondeu em LockTTL,
+// a repetição assume a chave com um novo LeaseToken e é processada.
+func (s *IdempotencyService) Begin(ctx context.Context, key, fingerprint string) (*idempotency.Record, error) {
+	now := s.now().UTC()
+	existing, err := s.repo.Get(ctx, key)
+	switch {
+	case errors.Is(err, idempotency.ErrNotFound):
+	case err != nil:
+		return nil, err
+	case existing.Expired(now):
+		if err := s.repo.Delete(ctx, key); err != nil {
+			return nil, err
+		}
+	default:
+		return s.replay(ctx, existing, fingerprint, now)
+	}
+
+	record := &idempotency.Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockedUntil: now.Add(s.settings.LockTTL),
+		LeaseToken:  uuid.New().String(),
+		ExpiresAt:   now.Add(s.settings.KeyTTL),
+		CreatedAt:   now,
+	}
+	err = s.repo.Create(ctx, record)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, idempotency.ErrKeyExists) {
+		return nil, err
+	}
+
+	// Uma requisição simultânea com a mesma chave gravou primeiro
+	if existing, err = s.repo.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.replay(ctx, existing, fingerprint, now)
+}
+
+// replay responde à repetição de uma requisição registrada. Se a primeira foi abandonada, a repetição
+// assume a chave e o registro retornado está em andamento, com o novo LeaseToken.
+func (s *IdempotencyService) replay(ctx context.Context, existing *idempotency.Record, fingerprint string, now time.Time) (*idempotency.Record, error) {
+	if existing.Fingerprint != fingerprint || !existing.Abandoned(now) {
+		return checkReplay(existing, fingerprint)
+	}
+
+	lease := uuid.New().String()
+	acquired, err := s.repo.TakeOver(ctx, existing.Key, lease, now.Add(s.settings.LockTTL), now)
+	if err != nil {
+		return nil, err
+	}
+	if acquired {
+		existing.LockedUntil = now.Add(s.settings.LockTTL)
+		existing.LeaseToken = lease
+		return existing, nil
+	}
+
+	// Outra repetição assumiu a chave ou a primeira requisição terminou nesse meio tempo
+	if existing, err = s.repo.Get(ctx, existing.Key); err != nil {
+		return nil, err
+	}
+	return checkReplay(existing, fingerprint)
+}
+
+// Complete grava a resposta da requisição que detém lease. Falhas do servidor (5xx) liberam a chave para que
+// a repetição seja processada de novo, assim como uma falha ao gravar a resposta. Se uma repetição assumiu
+// a chave nesse meio tempo, nada é gravado nem liberado.
+func (s *IdempotencyService) Complete(ctx context.Context, key, lease string, statusCode int, contentType string, body []byte) error {
+	if statusCode >= 500 {
+		return s.repo.Release(ctx, key, lease)
+	}
+	err := s.repo.Complete(ctx, &idempotency.Record{
+		Key:         key,
+		LeaseToken:  lease,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+	})
+	if err != nil {
+		if releaseErr := s.repo.Release(ctx, key, lease); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Abandon libera a chave de uma requisição interrompida antes de responder, se ela ainda detiver lease
+func (s *IdempotencyService) Abandon(ctx context.Context, key, lease string) error {
+	return s.repo.Release(ctx, key, lease)
+}
+
+// PurgeExpired apaga as chaves vencidas
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) error {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
+
+// checkReplay compara a repetição com a requisição registrada
+func checkReplay(record *idempotency.Record, fingerprint string) (*idempotency.Record, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, idempotency.ErrKeyReused
+	}
+	if !record.Completed() {
+		return nil, idempotency.ErrInProgress
+	}
+	return record, nil
+}
@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/idempotency"
+	"youmeet/internal/core/services"
+)
+
+// clock é um relógio manual, avançado pelos testes
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newIdempotencyService(t *testing.T, lockTTL time.Duration) (*services.IdempotencyService, *clock) {
+	t.Helper()
+	s := services.NewIdempotencyService(repositories.NewIdempotencyRepository(newTestDB(t)), services.IdempotencySettings{
+		KeyTTL:  time.Hour,
+		LockTTL: lockTTL,
+	})
+	c := &clock{now: time.Now()}
+	s.SetClock(c.Now)
+	return s, c
+}
+
+// begin inicia uma requisição que deve ser processada e retorna o LeaseToken que a detém
+func begin(t *testing.T, s *services.IdempotencyService, key, fingerprint string) string {
+	t.Helper()
+	record, err := s.Begin(context.Background(), key, fingerprint)
+	if err != nil || record == nil || record.Completed() || record.LeaseToken == "" {
+		t.Fatalf("requisição a processar: record = %+v, err = %v", record, err)
+	}
+	return record.LeaseToken
+}
+
+func TestIdempotencyReplaysCompletedResponse(t *testing.T) {
+	s, _ := newIdempotencyService(t, time.Minute)
+	ctx := context.Background()
+
+	lease := begin(t, s, "user:1:chave", "req-a")
+	if _, err := s.Begin(ctx, "user:1:chave", "req-a"); !errors.Is(err, idempotency.ErrInProgress) {
+		t.Fatalf("repetição em andamento: erro = %v, esperado %v", err, idempotency.ErrInProgress)
+	}
+	if _, err := s.Begin(ctx, "user:1:chave", "req-b"); !errors.Is(err, idempotency.ErrKeyReused) {
+		t.Fatalf("outra requisição: erro = %v, esperado %v", err, idempotency.ErrKeyReused)
+	}
+	if err := s.Complete(ctx, "user:1:chave", lease, http.StatusCreated, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	record, err := s.Begin(ctx, "user:1:chave", "req-a")
+	if err != nil {
+		t.Fatalf("repetição concluída: %v", err)
+	}
+	if !record.Completed() || record.StatusCode != http.StatusCreated || string(record.Body) != `{"id":"1"}` {
+		t.Fatalf("resposta gravada inesperada: %+v", record)
+	}
+}
+
+func TestIdempotencyConcurrentFirstRequestsProcessOnce(t *testing.T) {
+	s, _ := newIdempotencyService(t, time.Minute)
+
+	const requests = 8
+	errs := make([]error, requests)
+	ready := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-ready
+			_, errs[i] = s.Begin(context.Background(), "user:1:chave", "req-a")
+		}(i)
+	}
+	close(ready)
+	wg.Wait()
+
+	processed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			processed++
+		case !errors.Is(err, idempotency.ErrInProgress):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if processed != 1 {
+		t.Fatalf("%d requisições simultâneas foram processadas, esperado 1", processed)
+	}
+}
+
+func TestIdempotencyRepetitionTakesOverAbandonedKey(t *testing.T) {
+	s, c := newIdempotencyService(t, time.Minute)
+	ctx := context.Background()
+
+	first := begin(t, s, "user:1:chave", "req-a")
+	// A primeira requisição não responde dentro do prazo; a repetição assume a chave
+	c.Advance(time.Minute)
+
+	if _, err := s.Begin(ctx, "user:1:chave", "req-b"); !errors.Is(err, idempotency.ErrKeyReused) {
+		t.Fatalf("outra requisição: erro = %v, esperado %v", err, idempotency.ErrKeyReused)
+	}
+	second := begin(t, s, "user:1:chave", "req-a")
+	if second == first {
+		t.Fatal("a repetição assumiu a chave com o LeaseToken da primeira requisição")
+	}
+	if _, err := s.Begin(ctx, "user:1:chave", "req-a"); !errors.Is(err, idempotency.ErrInProgress) {
+		t.Fatalf("a chave assumida não foi renovada: erro = %v", err)
+	}
+
+	// A primeira requisição termina depois: não libera nem sobrescreve a chave da repetição
+	if err := s.Abandon(ctx, "user:1:chave", first); err != nil {
+		t.Fatalf("Abandon: %v", err)
+	}
+	if err := s.Complete(ctx, "user:1:chave", first, http.StatusCreated, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if _, err := s.Begin(ctx, "user:1:chave", "req-a"); !errors.Is(err, idempotency.ErrInProgress) {
+		t.Fatalf("a primeira requisição alterou a chave assumida: erro = %v", err)
+	}
+
+	if err := s.Complete(ctx, "user:1:chave", second, http.StatusCreated, "application/json", []byte(`{"id":"2"}`)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	record, err := s.Begin(ctx, "user:1:chave", "req-a")
+	if err != nil || string(record.Body) != `{"id":"2"}` {
+		t.Fatalf("resposta gravada = %+v, %v, esperado a da repetição", record, err)
+	}
+}
+
+func TestIdempotencyServerErrorReleasesKey(t *testing.T) {
+	s, _ := newIdempotencyService(t, time.Minute)
+	ctx := context.Background()
+
+	lease := begin(t, s, "user:1:chave", "req-a")
+	if err := s.Complete(ctx, "user:1:chave", lease, http.StatusInternalServerError, "application/json", nil); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	begin(t, s, "user:1:chave", "req-a")
+}
+
+func TestIdempotencyExpiredKeyStartsOver(t *testing.T) {
+	s, c := newIdempotencyService(t, time.Minute)
+	ctx := context.Background()
+
+	lease := begin(t, s, "user:1:chave", "req-a")
+	if err := s.Complete(ctx, "user:1:chave", lease, http.StatusCreated, "application/json", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	c.Advance(time.Hour)
+
+	// Vencida, a chave volta a valer para qualquer requisição
+	begin(t, s, "user:1:chave", "req-b")
+}
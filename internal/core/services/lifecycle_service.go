@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+
+	"github.com/google/uuid"
+)
+
+// LifecycleService conduz os agendamentos pelas transições de status
+type LifecycleService struct {
+	appointmentRepo appointment.Repository
+	policies        *PolicyService
+	waitlist        *WaitlistService
+	tx              appointment.TransactionManager
+}
+
+func NewLifecycleService(appointmentRepo appointment.Repository, policies *PolicyService, waitlist *WaitlistService, tx appointment.TransactionManager) *LifecycleService {
+	return &LifecycleService{
+		appointmentRepo: appointmentRepo,
+		policies:        policies,
+		waitlist:        waitlist,
+		tx:              tx,
+	}
+}
+
+// Transition aplica a transição ao agendamento, registrando quem a fez, quando e o motivo informado
+func (s *LifecycleService) Transition(ctx context.Context, appointmentID uuid.UUID, transition appointment.Transition, actorID uuid.UUID, reason string) (*appointment.Appointment, error) {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := s.apply(ctx, []*appointment.Appointment{appt}, func(ctx context.Context, _ int, appt *appointment.Appointment) (*appointment.Appointment, error) {
+		return s.transition(ctx, appt.ID, transition, actorID, reason, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed[0], nil
+}
+
+// Cancel cancela em nome do cliente ou do profissional. Cancelamentos do cliente seguem a política:
+// dentro da antecedência mínima são recusados ou, se a política aceitar, marcados como tardios com a taxa.
+func (s *LifecycleService) Cancel(ctx context.Context, appointmentID uuid.UUID, actor appointment.Actor, reason string) (*appointment.Appointment, error) {
+	cancelled, err := s.CancelOccurrences(ctx, appointmentID, actor, reason, appointment.ScopeThis)
+	if err != nil {
+		return nil, err
+	}
+	return cancelled[0], nil
+}
+
+// CancelOccurrences cancela o agendamento e, conforme o escopo, as ocorrências seguintes ou toda a série,
+// na mesma transação: se a política recusar alguma ocorrência, nenhuma é cancelada
+func (s *LifecycleService) CancelOccurrences(ctx context.Context, appointmentID uuid.UUID, actor appointment.Actor, reason, scope string) ([]*appointment.Appointment, error) {
+	anchor, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	appts, err := loadOccurrences(ctx, s.appointmentRepo, anchor, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	transition := appointment.CancelByProvider
+	var check func(ctx context.Context, appt *appointment.Appointment) error
+	if !actor.Provider {
+		transition = appointment.CancelByClient
+		policies, err := s.policies.effectiveByProfessional(ctx, appts)
+		if err != nil {
+			return nil, err
+		}
+		check = func(ctx context.Context, appt *appointment.Appointment) error {
+			policy, ok := policies[appt.ProfessionalID]
+			if !ok {
+				return fmt.Errorf("%w: o agendamento foi alterado por outra requisição", appointment.ErrInvalidTransition)
+			}
+			late, err := policy.CheckCancellation(appt, time.Now())
+			if err != nil || !late {
+				return err
+			}
+			appt.LateCancellation = true
+			appt.CancellationFee = policy.LateCancellationFee
+			return s.appointmentRepo.RecordLateCancellation(ctx, appt.ID, appt.CancellationFee)
+		}
+	}
+
+	return s.apply(ctx, appts, func(ctx context.Context, i int, appt *appointment.Appointment) (*appointment.Appointment, error) {
+		cancelled, err := s.transition(ctx, appt.ID, transition, actor.UserID, reason, check)
+		if err != nil {
+			return nil, occurrenceError(appts, i, err)
+		}
+		return cancelled, nil
+	})
+}
+
+// apply chama change para cada agendamento em uma única transação, com as agendas dos profissionais bloqueadas,
+// e na mesma transação reserva os horários liberados para a lista de espera. Os emails das ofertas são enviados
+// depois da confirmação; falhas no envio não desfazem a transição e ficam apenas registradas no log.
+func (s *LifecycleService) apply(ctx context.Context, appts []*appointment.Appointment, change func(ctx context.Context, i int, appt *appointment.Appointment) (*appointment.Appointment, error)) ([]*appointment.Appointment, error) {
+	locks := make([]uuid.UUID, 0, len(appts))
+	for _, a := range appts {
+		locks = append(locks, a.ProfessionalID)
+	}
+
+	changed := make([]*appointment.Appointment, 0, len(appts))
+	var offers []*waitlistOffer
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Bloqueia as agendas na mesma ordem das remarcações para que as duas operações não travem entre si
+		for _, id := range uniqueSorted(locks) {
+			if err := s.appointmentRepo.LockProfessionalSchedule(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		for i, a := range appts {
+			appt, err := change(ctx, i, a)
+			if err != nil {
+				return err
+			}
+			if appt.ProfessionalID != a.ProfessionalID {
+				return occurrenceError(appts, i, fmt.Errorf("%w: o agendamento foi alterado por outra requisição", appointment.ErrInvalidTransition))
+			}
+			changed = append(changed, appt)
+			if !appt.Frees() {
+				continue
+			}
+			offer, err := s.waitlist.slotFreed(ctx, appt)
+			if err != nil {
+				return err
+			}
+			offers = append(offers, offer)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.waitlist.notify(ctx, offers...); err != nil {
+		log.Printf("waitlist: %v", err)
+	}
+	return changed, nil
+}
+
+// transition valida e grava a transição; check roda na mesma transação, depois da validação
+func (s *LifecycleService) transition(ctx context.Context, appointmentID uuid.UUID, transition appointment.Transition, actorID uuid.UUID, reason string, check func(ctx context.Context, appt *appointment.Appointment) error) (*appointment.Appointment, error) {
+	var appt *appointment.Appointment
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		appt, err = s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+		if err != nil {
+			return err
+		}
+
+		change, err := appt.Apply(transition, actorID, strings.TrimSpace(reason), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(ctx, appt); err != nil {
+				return err
+			}
+		}
+		if err := s.appointmentRepo.ChangeStatus(ctx, change); err != nil {
+			return err
+		}
+
+		appt.Status = change.ToStatus
+		appt.StatusChangedAt = &change.CreatedAt
+		appt.StatusHistory = append(appt.StatusHistory, *change)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appt, nil
+}
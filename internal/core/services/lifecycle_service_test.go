@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"youmeet/internal/core/domain/appointment"
+
+	"github.com/google/uuid"
+)
+
+func TestLifecycleRecordsStatusHistory(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if appt.Status != appointment.StatusPending {
+		t.Fatalf("status = %s, esperado %s", appt.Status, appointment.StatusPending)
+	}
+
+	actorID := uuid.New()
+	for _, transition := range []appointment.Transition{appointment.Confirm, appointment.CheckIn, appointment.Complete} {
+		if appt, err = f.lifecycle.Transition(ctx, appt.ID, transition, actorID, "  "+transition.Action+"  "); err != nil {
+			t.Fatalf("%s: %v", transition.Action, err)
+		}
+	}
+
+	stored, err := f.appointments.GetAppointmentByID(ctx, appt.ID)
+	if err != nil {
+		t.Fatalf("GetAppointmentByID: %v", err)
+	}
+	if stored.Status != appointment.StatusCompleted || stored.StatusChangedAt == nil {
+		t.Fatalf("agendamento = %+v, esperado concluído", stored)
+	}
+	// O histórico começa na criação e guarda cada transição em ordem
+	want := []string{appointment.StatusPending, appointment.StatusConfirmed, appointment.StatusCheckedIn, appointment.StatusCompleted}
+	if len(stored.StatusHistory) != len(want) {
+		t.Fatalf("%d registros no histórico, esperado %d", len(stored.StatusHistory), len(want))
+	}
+	for i, change := range stored.StatusHistory {
+		if change.ToStatus != want[i] {
+			t.Fatalf("histórico[%d] = %s, esperado %s", i, change.ToStatus, want[i])
+		}
+	}
+	if last := stored.StatusHistory[3]; last.ChangedBy != actorID || last.Reason != appointment.Complete.Action {
+		t.Fatalf("última transição = %+v, esperado o autor e o motivo sem espaços", last)
+	}
+}
+
+func TestLifecycleRejectsInvalidTransitions(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+
+	if _, err := f.lifecycle.Transition(ctx, appt.ID, appointment.CheckIn, uuid.New(), ""); !errors.Is(err, appointment.ErrInvalidTransition) {
+		t.Fatalf("chegada sem confirmação: erro = %v, esperado %v", err, appointment.ErrInvalidTransition)
+	}
+	if _, err := f.lifecycle.Transition(ctx, appt.ID, appointment.Confirm, uuid.New(), ""); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	// O agendamento ainda não começou
+	if _, err := f.lifecycle.Transition(ctx, appt.ID, appointment.MarkNoShow, uuid.New(), ""); !errors.Is(err, appointment.ErrInvalidTransition) {
+		t.Fatalf("falta antes do início: erro = %v, esperado %v", err, appointment.ErrInvalidTransition)
+	}
+	if _, err := f.lifecycle.Transition(ctx, uuid.New(), appointment.Confirm, uuid.New(), ""); !errors.Is(err, appointment.ErrNotFound) {
+		t.Fatalf("agendamento inexistente: erro = %v, esperado %v", err, appointment.ErrNotFound)
+	}
+}
+
+func TestConcurrentTransitionsApplyOnce(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+
+	errs := make(chan error, 2)
+	for _, transition := range []appointment.Transition{appointment.CancelByClient, appointment.CancelByProvider} {
+		go func(transition appointment.Transition) {
+			_, err := f.lifecycle.Transition(ctx, appt.ID, transition, uuid.New(), "")
+			errs <- err
+		}(transition)
+	}
+
+	applied := 0
+	for i := 0; i < 2; i++ {
+		switch err := <-errs; {
+		case err == nil:
+			applied++
+		case !errors.Is(err, appointment.ErrInvalidTransition):
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if applied != 1 {
+		t.Fatalf("%d cancelamentos aplicados, esperado 1", applied)
+	}
+}
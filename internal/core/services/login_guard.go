@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+
+	"github.com/google/uuid"
+)
+
+// LoginGuardSettings define os limites de tentativas de login
+type LoginGuardSettings struct {
+	MaxFailuresPerEmail int
+	MaxFailuresPerIP    int
+	FreeAttempts        int
+	BaseDelay           time.Duration
+	MaxDelay            time.Duration
+	LockoutDuration     time.Duration
+}
+
+// LoginGuard controla falhas de login por email e por IP, aplicando atrasos
+// progressivos e bloqueio temporário ao atingir o limite de falhas.
+type LoginGuard struct {
+	throttleRepo auth.LoginThrottleRepository
+	auditRepo    audit.Repository
+	settings     LoginGuardSettings
+}
+
+func NewLoginGuard(throttleRepo auth.LoginThrottleRepository, auditRepo audit.Repository, settings LoginGuardSettings) *LoginGuard {
+	return &LoginGuard{
+		throttleRepo: throttleRepo,
+		auditRepo:    auditRepo,
+		settings:     settings,
+	}
+}
+
+// Check retorna *auth.ThrottleError se o email ou o IP ainda precisam aguardar
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+
+	for _, key := range g.keys(email, ip) {
+		throttle, err := g.throttleRepo.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if d := g.retryAfter(throttle, now); d > wait {
+			wait = d
+		}
+	}
+
+	if wait > 0 {
+		return &auth.ThrottleError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure contabiliza a falha para o email e o IP, bloqueando-os ao atingir o limite
+func (g *LoginGuard) RecordFailure(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	for _, key := range g.keys(email, ip) {
+		// A contagem e a decisão de bloquear acontecem com o registro bloqueado, então
+		// falhas simultâneas não se perdem e apenas uma delas inicia o bloqueio
+		locked := false
+		throttle, err := g.throttleRepo.Update(ctx, key, func(throttle *auth.LoginThrottle) {
+			// Falhas antigas ou de um bloqueio já encerrado não contam mais
+			if throttle.LockedUntil != nil && now.After(*throttle.LockedUntil) ||
+				now.Sub(throttle.LastFailureAt) > g.settings.LockoutDuration {
+				throttle.Failures = 0
+				throttle.LockedUntil = nil
+			}
+
+			throttle.Failures++
+			throttle.LastFailureAt = now
+
+			if throttle.LockedUntil == nil && throttle.Failures >= g.maxFailures(key) {
+				lockedUntil := now.Add(g.settings.LockoutDuration)
+				throttle.LockedUntil = &lockedUntil
+				locked = true
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			if err := g.auditLockout(ctx, throttle, ip); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordSuccess zera as falhas do email após um login bem-sucedido.
+// As falhas do IP são mantidas para não permitir que uma conta válida
+// sirva para testar senhas de outras contas.
+func (g *LoginGuard) RecordSuccess(ctx context.Context, email string) error {
+	return g.throttleRepo.Reset(ctx, emailKey(email))
+}
+
+func (g *LoginGuard) retryAfter(throttle *auth.LoginThrottle, now time.Time) time.Duration {
+	if throttle.LockedUntil != nil {
+		if now.Before(*throttle.LockedUntil) {
+			return throttle.LockedUntil.Sub(now)
+		}
+		return 0
+	}
+
+	delay := g.delay(throttle.Failures)
+	if next := throttle.LastFailureAt.Add(delay); now.Before(next) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// delay dobra a espera a cada falha além das tentativas livres, até MaxDelay
+func (g *LoginGuard) delay(failures int) time.Duration {
+	extra := failures - g.settings.FreeAttempts
+	if extra <= 0 {
+		return 0
+	}
+
+	delay := g.settings.BaseDelay
+	for i := 1; i < extra && delay < g.settings.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > g.settings.MaxDelay {
+		delay = g.settings.MaxDelay
+	}
+	return delay
+}
+
+func (g *LoginGuard) maxFailures(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return g.settings.MaxFailuresPerIP
+	}
+	return g.settings.MaxFailuresPerEmail
+}
+
+func (g *LoginGuard) keys(email, ip string) []string {
+	keys := []string{emailKey(email)}
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+func (g *LoginGuard) auditLockout(ctx context.Context, throttle *auth.LoginThrottle, ip string) error {
+	return g.auditRepo.Create(ctx, &audit.AuditLog{
+		ID:        uuid.New(),
+		Action:    audit.ActionLoginLockout,
+		IP:        ip,
+		Details:   fmt.Sprintf("key=%s failures=%d locked_until=%s", throttle.Key, throttle.Failures, throttle.LockedUntil.Format(time.RFC3339)),
+		CreatedAt: time.Now(),
+	})
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,150 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/audit"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/services"
+)
+
+func newLoginGuard(t *testing.T, settings services.LoginGuardSettings) (*services.LoginGuard, repositories.DBClient) {
+	t.Helper()
+	db := newTestDB(t)
+	return services.NewLoginGuard(repositories.NewLoginThrottleRepository(db), repositories.NewAuditRepository(db), settings), db
+}
+
+func TestLoginLocksEmailAfterMaxFailures(t *testing.T) {
+	f := newAuthFixtureWithGuard(t, services.LoginGuardSettings{
+		MaxFailuresPerEmail: 3,
+		MaxFailuresPerIP:    100,
+		FreeAttempts:        10,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		LockoutDuration:     15 * time.Minute,
+	})
+	ctx := context.Background()
+	f.login(t, "ana@example.com", "client")
+
+	for i := 0; i < 3; i++ {
+		_, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-errada", IP: "10.0.0.1"})
+		if !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Fatalf("tentativa %d: erro = %v, esperado ErrInvalidCredentials", i+1, err)
+		}
+	}
+
+	// Nem a senha correta é aceita durante o bloqueio
+	_, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123", IP: "10.0.0.2"})
+	var throttleErr *auth.ThrottleError
+	if !errors.As(err, &throttleErr) || !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Fatalf("erro = %v, esperado ThrottleError", err)
+	}
+	if throttleErr.RetryAfter <= 14*time.Minute {
+		t.Fatalf("RetryAfter = %s, esperado perto de 15m", throttleErr.RetryAfter)
+	}
+
+	var logs []audit.AuditLog
+	if err := f.db.Find(&logs, "action = ?", audit.ActionLoginLockout); err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("%d registros de bloqueio na auditoria, esperado 1", len(logs))
+	}
+}
+
+func TestLoginGuardDelaysAfterFreeAttempts(t *testing.T) {
+	guard, _ := newLoginGuard(t, services.LoginGuardSettings{
+		MaxFailuresPerEmail: 100,
+		MaxFailuresPerIP:    100,
+		FreeAttempts:        1,
+		BaseDelay:           time.Minute,
+		MaxDelay:            3 * time.Minute,
+		LockoutDuration:     time.Hour,
+	})
+	ctx := context.Background()
+
+	if err := guard.RecordFailure(ctx, "ana@example.com", ""); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	if err := guard.Check(ctx, "ana@example.com", ""); err != nil {
+		t.Fatalf("a primeira falha não deveria atrasar: %v", err)
+	}
+
+	// A espera dobra a cada falha e não passa de MaxDelay
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		if err := guard.RecordFailure(ctx, "ana@example.com", ""); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+		var throttleErr *auth.ThrottleError
+		if err := guard.Check(ctx, "ANA@example.com ", ""); !errors.As(err, &throttleErr) {
+			t.Fatalf("falha %d: erro = %v, esperado ThrottleError", i+2, err)
+		}
+		if throttleErr.RetryAfter > want || throttleErr.RetryAfter < want-time.Second {
+			t.Fatalf("falha %d: RetryAfter = %s, esperado %s", i+2, throttleErr.RetryAfter, want)
+		}
+	}
+}
+
+func TestLoginGuardLocksIPAcrossEmails(t *testing.T) {
+	guard, _ := newLoginGuard(t, services.LoginGuardSettings{
+		MaxFailuresPerEmail: 100,
+		MaxFailuresPerIP:    3,
+		FreeAttempts:        100,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		LockoutDuration:     15 * time.Minute,
+	})
+	ctx := context.Background()
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := guard.RecordFailure(ctx, email, "10.0.0.1"); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+
+	if err := guard.Check(ctx, "d@example.com", "10.0.0.1"); !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Fatalf("erro = %v, esperado ErrTooManyAttempts para o IP bloqueado", err)
+	}
+	if err := guard.Check(ctx, "d@example.com", "10.0.0.2"); err != nil {
+		t.Fatalf("outro IP não deveria ser bloqueado: %v", err)
+	}
+}
+
+func TestLoginGuardSuccessResetsOnlyEmail(t *testing.T) {
+	guard, _ := newLoginGuard(t, services.LoginGuardSettings{
+		MaxFailuresPerEmail: 2,
+		MaxFailuresPerIP:    3,
+		FreeAttempts:        100,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		LockoutDuration:     15 * time.Minute,
+	})
+	ctx := context.Background()
+
+	if err := guard.RecordFailure(ctx, "ana@example.com", "10.0.0.1"); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	if err := guard.RecordSuccess(ctx, "ana@example.com"); err != nil {
+		t.Fatalf("RecordSuccess: %v", err)
+	}
+
+	// O email volta a ter todas as tentativas, mas o IP continua contando
+	for i := 0; i < 2; i++ {
+		if err := guard.RecordFailure(ctx, "bruno@example.com", "10.0.0.1"); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+	if err := guard.RecordFailure(ctx, "ana@example.com", ""); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	if err := guard.Check(ctx, "ana@example.com", ""); err != nil {
+		t.Fatalf("email não deveria estar bloqueado após o sucesso: %v", err)
+	}
+	if err := guard.Check(ctx, "carla@example.com", "10.0.0.1"); !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Fatalf("erro = %v, esperado o IP bloqueado", err)
+	}
+}
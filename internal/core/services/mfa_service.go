@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+const recoveryCodeCount = 10
+
+var recoveryEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+type MFAService struct {
+	userRepo     user.UserRepository
+	companyRepo  user.CompanyRepository
+	recoveryRepo auth.RecoveryCodeRepository
+	issuer       string
+}
+
+func NewMFAService(userRepo user.UserRepository, companyRepo user.CompanyRepository, recoveryRepo auth.RecoveryCodeRepository, issuer string) *MFAService {
+	return &MFAService{
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		recoveryRepo: recoveryRepo,
+		issuer:       issuer,
+	}
+}
+
+// Enroll gera um novo segredo TOTP pendente de confirmação
+func (s *MFAService) Enroll(ctx context.Context, userID uuid.UUID) (*auth.MFAEnrollment, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, auth.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	u.TOTPSecret = secret
+	u.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return &auth.MFAEnrollment{
+		Secret: secret,
+		URI:    totpURI(s.issuer, u.Email, secret),
+	}, nil
+}
+
+// Confirm ativa o segundo fator após validar o primeiro código e retorna
+// os códigos de recuperação, exibidos apenas uma vez
+func (s *MFAService) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, auth.ErrMFAAlreadyEnabled
+	}
+	if u.TOTPSecret == "" {
+		return nil, auth.ErrMFANotEnrolled
+	}
+
+	step, ok := validateTOTP(u.TOTPSecret, code, time.Now(), u.TOTPLastStep)
+	if !ok {
+		return nil, auth.ErrInvalidMFACode
+	}
+
+	codes, err := s.regenerateRecoveryCodes(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.TOTPEnabled = true
+	u.TOTPLastStep = step
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyCode valida um código TOTP ou consome um código de recuperação
+func (s *MFAService) VerifyCode(ctx context.Context, u *user.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	if step, ok := validateTOTP(u.TOTPSecret, code, time.Now(), u.TOTPLastStep); ok {
+		u.TOTPLastStep = step
+		return s.userRepo.Update(ctx, u)
+	}
+
+	used, err := s.recoveryRepo.Use(ctx, u.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return auth.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// SetCompanyPolicy define se todos os profissionais da empresa devem usar 2FA
+func (s *MFAService) SetCompanyPolicy(ctx context.Context, companyID uuid.UUID, required bool) error {
+	return s.companyRepo.SetRequireProfessionalMFA(ctx, companyID, required)
+}
+
+func (s *MFAService) regenerateRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	now := time.Now()
+	plain := make([]string, 0, recoveryCodeCount)
+	records := make([]*auth.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := recoveryEncoding.EncodeToString(buf)
+		code := raw[:4] + "-" + raw[4:]
+
+		plain = append(plain, code)
+		records = append(records, &auth.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hashToken(normalizeRecoveryCode(code)),
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/services"
+)
+
+// enableMFA ativa o 2FA do usuário e retorna o segredo e os códigos de recuperação
+func enableMFA(t *testing.T, f *authFixture, resp *auth.AuthResponse) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := f.mfa.Enroll(ctx, resp.User.ID)
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	if _, err := f.mfa.Confirm(ctx, resp.User.ID, "12345"); !errors.Is(err, auth.ErrInvalidMFACode) {
+		t.Fatalf("Confirm com código errado: erro = %v, esperado ErrInvalidMFACode", err)
+	}
+	codes, err := f.mfa.Confirm(ctx, resp.User.ID, services.TOTPCodeAt(enrollment.Secret, time.Now()))
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	return enrollment.Secret, codes
+}
+
+func TestLoginWithMFARequiresSecondFactor(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	secret, _ := enableMFA(t, f, f.login(t, "ana@example.com", "client"))
+
+	challenge, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !challenge.MFARequired || challenge.ChallengeToken == "" || challenge.Token != "" {
+		t.Fatalf("login com 2FA deveria retornar apenas o desafio: %+v", challenge)
+	}
+
+	// O desafio não serve como token de acesso
+	if _, err := f.service.ValidateToken(ctx, challenge.ChallengeToken); err == nil {
+		t.Fatal("o token de desafio foi aceito como token de acesso")
+	}
+
+	// O código usado na confirmação não pode ser reaproveitado
+	_, err = f.service.VerifyMFA(ctx, &auth.MFAVerifyRequest{ChallengeToken: challenge.ChallengeToken, Code: services.TOTPCodeAt(secret, time.Now())})
+	if !errors.Is(err, auth.ErrInvalidMFACode) {
+		t.Fatalf("erro = %v, esperado ErrInvalidMFACode para código repetido", err)
+	}
+
+	session, err := f.service.VerifyMFA(ctx, &auth.MFAVerifyRequest{ChallengeToken: challenge.ChallengeToken, Code: services.TOTPCodeAt(secret, time.Now().Add(30*time.Second))})
+	if err != nil {
+		t.Fatalf("VerifyMFA: %v", err)
+	}
+	if _, err := f.service.ValidateToken(ctx, session.Token); err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+}
+
+func TestRecoveryCodeIsSingleUse(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	_, codes := enableMFA(t, f, f.login(t, "ana@example.com", "client"))
+	if len(codes) != 10 {
+		t.Fatalf("%d códigos de recuperação, esperado 10", len(codes))
+	}
+
+	verify := func(code string) error {
+		challenge, err := f.service.Login(ctx, &auth.AuthRequest{Email: "ana@example.com", Password: "senha-segura-123"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		_, err = f.service.VerifyMFA(ctx, &auth.MFAVerifyRequest{ChallengeToken: challenge.ChallengeToken, Code: code})
+		return err
+	}
+
+	if err := verify(codes[0]); err != nil {
+		t.Fatalf("código de recuperação recusado: %v", err)
+	}
+	if err := verify(codes[0]); !errors.Is(err, auth.ErrInvalidMFACode) {
+		t.Fatalf("erro = %v, esperado ErrInvalidMFACode para código já usado", err)
+	}
+}
+
+func TestEnrollRejectsActiveMFA(t *testing.T) {
+	f := newAuthFixture(t)
+	resp := f.login(t, "ana@example.com", "client")
+	enableMFA(t, f, resp)
+
+	if _, err := f.mfa.Enroll(context.Background(), resp.User.ID); !errors.Is(err, auth.ErrMFAAlreadyEnabled) {
+		t.Fatalf("erro = %v, esperado ErrMFAAlreadyEnabled", err)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// OIDCService implementa o login social com authorization code + PKCE
+type OIDCService struct {
+	providers    map[string]auth.IdentityProvider
+	stateRepo    auth.OAuthStateRepository
+	identityRepo auth.UserIdentityRepository
+	userRepo     user.UserRepository
+	authService  *AuthService
+	stateTTL     time.Duration
+}
+
+func NewOIDCService(providers []auth.IdentityProvider, stateRepo auth.OAuthStateRepository, identityRepo auth.UserIdentityRepository, userRepo user.UserRepository, authService *AuthService, stateTTL time.Duration) *OIDCService {
+	byName := make(map[string]auth.IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		providers:    byName,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		stateTTL:     stateTTL,
+	}
+}
+
+// Begin registra uma nova autorização e retorna a URL do provedor para onde o usuário deve ir
+func (s *OIDCService) Begin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", auth.ErrUnknownProvider
+	}
+
+	rawState, stateHash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, _, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	verifier, _, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	state := &auth.OAuthState{
+		ID:           uuid.New(),
+		Provider:     providerName,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.stateTTL),
+		CreatedAt:    now,
+	}
+	if err := s.stateRepo.Create(ctx, state); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(rawState, nonce, pkceChallenge(verifier)), nil
+}
+
+// Complete valida o retorno do provedor e inicia a sessão do usuário vinculado.
+// Identidades novas são vinculadas pelo email verificado ou geram uma conta de cliente.
+func (s *OIDCService) Complete(ctx context.Context, providerName, rawState, code string) (*auth.AuthResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, auth.ErrUnknownProvider
+	}
+
+	state, err := s.stateRepo.GetByHash(ctx, hashToken(rawState))
+	if err != nil {
+		return nil, err
+	}
+	if state.Provider != providerName || state.UsedAt != nil || time.Now().After(state.ExpiresAt) {
+		return nil, auth.ErrInvalidOAuthState
+	}
+	used, err := s.stateRepo.MarkUsed(ctx, state.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, auth.ErrInvalidOAuthState
+	}
+
+	external, err := provider.Exchange(ctx, code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrExternalAuthFailed, err)
+	}
+
+	u, err := s.resolveUser(ctx, external)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.startSession(ctx, u)
+}
+
+func (s *OIDCService) resolveUser(ctx context.Context, external *auth.ExternalIdentity) (*user.User, error) {
+	link, err := s.identityRepo.GetByProviderSubject(ctx, external.Provider, external.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, link.UserID)
+	}
+	if !errors.Is(err, auth.ErrIdentityNotLinked) {
+		return nil, err
+	}
+
+	// Só um email confirmado pelo provedor pode ser vinculado a uma conta
+	if !external.EmailVerified || external.Email == "" {
+		return nil, auth.ErrExternalEmailNotVerified
+	}
+	email := external.Email
+
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// O provedor comprovou a posse do email
+		if !u.IsEmailVerified() {
+			now := time.Now()
+			u.EmailVerifiedAt = &now
+			if err := s.userRepo.Update(ctx, u); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, user.ErrNotFound):
+		if u, err = s.createClient(ctx, external, email); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	link = &auth.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    u.ID,
+		Provider:  external.Provider,
+		Subject:   external.Subject,
+		Email:     email,
+		CreatedAt: time.Now(),
+	}
+	if err := s.identityRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (s *OIDCService) createClient(ctx context.Context, external *auth.ExternalIdentity, email string) (*user.User, error) {
+	name := external.Name
+	if name == "" {
+		name = email
+	}
+
+	now := time.Now()
+	u := &user.User{
+		ID:    uuid.New(),
+		Name:  name,
+		Email: email,
+		// Sem senha: o login por senha falha até o usuário definir uma pela redefinição
+		PasswordHash:    "",
+		Role:            "client",
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
+	}
+	if err := s.userRepo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
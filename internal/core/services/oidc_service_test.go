@@ -0,0 +1,199 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"youmeet/internal/adapters/repositories"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+	"youmeet/internal/infra/config"
+	"youmeet/internal/infra/oidc"
+	"youmeet/internal/infra/token"
+
+	"github.com/google/uuid"
+)
+
+type oidcFixture struct {
+	service  *services.OIDCService
+	provider *oidc.FakeProvider
+	userRepo *repositories.UserRepository
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	db := newTestDB(t)
+
+	tokens, err := token.NewJWTManager(config.JWTConfig{
+		Algorithm:  "HS256",
+		Secret:     "segredo-de-teste-com-mais-de-32-caracteres",
+		Issuer:     "youmeet-test",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	companyRepo := repositories.NewCompanyRepository(db)
+	loginGuard := services.NewLoginGuard(repositories.NewLoginThrottleRepository(db), repositories.NewAuditRepository(db), services.LoginGuardSettings{
+		MaxFailuresPerEmail: 5,
+		MaxFailuresPerIP:    20,
+		FreeAttempts:        3,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		LockoutDuration:     15 * time.Minute,
+	})
+	mfaService := services.NewMFAService(userRepo, companyRepo, repositories.NewRecoveryCodeRepository(db), "youmeet-test")
+	authService := services.NewAuthService(userRepo, companyRepo, repositories.NewProfessionalRepository(db), repositories.NewRefreshTokenRepository(db), tokens, loginGuard, mfaService, services.AuthSettings{
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		MFAChallengeTTL: 5 * time.Minute,
+	})
+
+	provider := oidc.NewFakeProvider("http://localhost:8080/oidc/fake/authorize", "http://localhost:8080/auth/oidc/fake/callback")
+	oidcService := services.NewOIDCService([]auth.IdentityProvider{provider}, repositories.NewOAuthStateRepository(db), repositories.NewUserIdentityRepository(db), userRepo, authService, 10*time.Minute)
+
+	return &oidcFixture{service: oidcService, provider: provider, userRepo: userRepo}
+}
+
+// authorize inicia o login e passa pela tela do provedor fake, retornando o state e o code do callback
+func (f *oidcFixture) authorize(t *testing.T, login url.Values) (string, string) {
+	t.Helper()
+
+	authURL, err := f.service.Begin(context.Background(), oidc.FakeProviderName)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	target, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("URL de autorização inválida: %v", err)
+	}
+	query := target.Query()
+	for name, values := range login {
+		query[name] = values
+	}
+	target.RawQuery = query.Encode()
+
+	rec := httptest.NewRecorder()
+	f.provider.Authorize(rec, httptest.NewRequest(http.MethodGet, target.String(), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("Authorize respondeu %d: %s", rec.Code, rec.Body.String())
+	}
+	callback, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("callback inválido: %v", err)
+	}
+	return callback.Query().Get("state"), callback.Query().Get("code")
+}
+
+func (f *oidcFixture) login(t *testing.T, login url.Values) (*auth.AuthResponse, error) {
+	t.Helper()
+	state, code := f.authorize(t, login)
+	return f.service.Complete(context.Background(), oidc.FakeProviderName, state, code)
+}
+
+func TestOIDCLoginCreatesAndReusesClientAccount(t *testing.T) {
+	f := newOIDCFixture(t)
+	login := url.Values{"email": {"ana@example.com"}, "name": {"Ana"}, "sub": {"ana-123"}}
+
+	first, err := f.login(t, login)
+	if err != nil {
+		t.Fatalf("primeiro login: %v", err)
+	}
+	if first.Token == "" || first.RefreshToken == "" {
+		t.Fatalf("sessão sem tokens: %+v", first)
+	}
+	if first.User.Role != "client" || first.User.Name != "Ana" || !first.User.IsEmailVerified() {
+		t.Fatalf("conta criada inesperada: %+v", first.User)
+	}
+
+	second, err := f.login(t, login)
+	if err != nil {
+		t.Fatalf("segundo login: %v", err)
+	}
+	if second.User.ID != first.User.ID {
+		t.Fatalf("a mesma identidade gerou outra conta: %s e %s", first.User.ID, second.User.ID)
+	}
+}
+
+func TestOIDCLoginLinksExistingAccountByVerifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	existing := &user.User{
+		ID:           uuid.New(),
+		Name:         "Bruno",
+		Email:        "bruno@example.com",
+		PasswordHash: "hash",
+		Role:         "professional",
+		CreatedAt:    time.Now(),
+	}
+	if err := f.userRepo.Create(context.Background(), existing); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	resp, err := f.login(t, url.Values{"email": {"bruno@example.com"}})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if resp.User.ID != existing.ID {
+		t.Fatalf("login vinculado a %s, esperado %s", resp.User.ID, existing.ID)
+	}
+
+	stored, err := f.userRepo.GetByID(context.Background(), existing.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !stored.IsEmailVerified() {
+		t.Fatal("o email confirmado pelo provedor não foi marcado como verificado")
+	}
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	_, err := f.login(t, url.Values{"email": {"carla@example.com"}, "email_verified": {"false"}})
+	if !errors.Is(err, auth.ErrExternalEmailNotVerified) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrExternalEmailNotVerified)
+	}
+	if _, err := f.userRepo.GetByEmail(context.Background(), "carla@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("uma conta foi criada para um email não verificado: %v", err)
+	}
+}
+
+func TestOIDCStateIsSingleUse(t *testing.T) {
+	f := newOIDCFixture(t)
+	state, code := f.authorize(t, url.Values{"email": {"ana@example.com"}})
+
+	if _, err := f.service.Complete(context.Background(), oidc.FakeProviderName, state, code); err != nil {
+		t.Fatalf("primeiro callback: %v", err)
+	}
+	_, err := f.service.Complete(context.Background(), oidc.FakeProviderName, state, code)
+	if !errors.Is(err, auth.ErrInvalidOAuthState) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrInvalidOAuthState)
+	}
+}
+
+func TestOIDCRejectsForgedCode(t *testing.T) {
+	f := newOIDCFixture(t)
+	state, _ := f.authorize(t, url.Values{"email": {"ana@example.com"}})
+
+	_, err := f.service.Complete(context.Background(), oidc.FakeProviderName, state, "codigo-forjado")
+	if !errors.Is(err, auth.ErrExternalAuthFailed) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrExternalAuthFailed)
+	}
+}
+
+func TestOIDCUnknownProvider(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	if _, err := f.service.Begin(context.Background(), "desconhecido"); !errors.Is(err, auth.ErrUnknownProvider) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrUnknownProvider)
+	}
+}
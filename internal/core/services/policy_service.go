@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// PolicyService gerencia as políticas de cancelamento e remarcação
+type PolicyService struct {
+	policyRepo appointment.PolicyRepository
+	profRepo   user.ProfessionalRepository
+}
+
+func NewPolicyService(policyRepo appointment.PolicyRepository, profRepo user.ProfessionalRepository) *PolicyService {
+	return &PolicyService{
+		policyRepo: policyRepo,
+		profRepo:   profRepo,
+	}
+}
+
+// GetCompanyPolicy retorna a política da empresa; sem política definida, nada é restringido
+func (s *PolicyService) GetCompanyPolicy(ctx context.Context, companyID uuid.UUID) (*appointment.Policy, error) {
+	return s.get(ctx, companyID)
+}
+
+func (s *PolicyService) SetCompanyPolicy(ctx context.Context, companyID uuid.UUID, policy *appointment.Policy) (*appointment.Policy, error) {
+	return s.save(ctx, companyID, policy)
+}
+
+// GetProfessionalPolicy retorna a política que vale para os agendamentos do profissional
+func (s *PolicyService) GetProfessionalPolicy(ctx context.Context, professionalID uuid.UUID) (*appointment.Policy, error) {
+	return s.Effective(ctx, professionalID)
+}
+
+// SetProfessionalPolicy define a política de um profissional autônomo
+func (s *PolicyService) SetProfessionalPolicy(ctx context.Context, professionalID uuid.UUID, policy *appointment.Policy) (*appointment.Policy, error) {
+	professional, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return nil, err
+	}
+	if professional.CompanyID != nil {
+		return nil, appointment.ErrPolicyManagedByCompany
+	}
+	return s.save(ctx, professionalID, policy)
+}
+
+// Effective retorna a política da empresa do profissional ou, se ele for autônomo, a dele
+func (s *PolicyService) Effective(ctx context.Context, professionalID uuid.UUID) (*appointment.Policy, error) {
+	professional, err := s.profRepo.GetProfessionalByID(ctx, professionalID)
+	if err != nil {
+		return nil, err
+	}
+	if professional.CompanyID != nil {
+		return s.get(ctx, *professional.CompanyID)
+	}
+	return s.get(ctx, professionalID)
+}
+
+// effectiveByProfessional retorna a política de cada profissional dos agendamentos
+func (s *PolicyService) effectiveByProfessional(ctx context.Context, appts []*appointment.Appointment) (map[uuid.UUID]*appointment.Policy, error) {
+	policies := make(map[uuid.UUID]*appointment.Policy)
+	for _, a := range appts {
+		if _, ok := policies[a.ProfessionalID]; ok {
+			continue
+		}
+		policy, err := s.Effective(ctx, a.ProfessionalID)
+		if err != nil {
+			return nil, err
+		}
+		policies[a.ProfessionalID] = policy
+	}
+	return policies, nil
+}
+
+func (s *PolicyService) get(ctx context.Context, ownerID uuid.UUID) (*appointment.Policy, error) {
+	policy, err := s.policyRepo.GetPolicy(ctx, ownerID)
+	if errors.Is(err, appointment.ErrPolicyNotFound) {
+		return &appointment.Policy{OwnerID: ownerID}, nil
+	}
+	return policy, err
+}
+
+func (s *PolicyService) save(ctx context.Context, ownerID uuid.UUID, policy *appointment.Policy) (*appointment.Policy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.policyRepo.GetPolicy(ctx, ownerID)
+	switch {
+	case err == nil:
+		policy.ID = existing.ID
+	case errors.Is(err, appointment.ErrPolicyNotFound):
+		policy.ID = uuid.New()
+	default:
+		return nil, err
+	}
+	policy.OwnerID = ownerID
+
+	if err := s.policyRepo.SavePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
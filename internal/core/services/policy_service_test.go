@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// setPolicy grava a política da empresa do profissional da fixture
+func (f *bookingFixture) setPolicy(t *testing.T, policy *appointment.Policy) {
+	t.Helper()
+	if _, err := f.policies.SetCompanyPolicy(context.Background(), *f.professional.CompanyID, policy); err != nil {
+		t.Fatalf("SetCompanyPolicy: %v", err)
+	}
+}
+
+func TestPolicyValidation(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	negative := -1
+
+	tests := []struct {
+		name   string
+		policy appointment.Policy
+	}{
+		{"antecedência negativa", appointment.Policy{MinNoticeMinutes: -10}},
+		{"limite de remarcações negativo", appointment.Policy{MaxReschedules: &negative}},
+		{"taxa negativa", appointment.Policy{AllowLateCancellation: true, LateCancellationFee: -5}},
+		{"taxa sem cancelamento tardio", appointment.Policy{LateCancellationFee: 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.policies.SetCompanyPolicy(ctx, *f.professional.CompanyID, &tt.policy)
+			if !errors.Is(err, appointment.ErrInvalidPolicy) {
+				t.Fatalf("erro = %v, esperado ErrInvalidPolicy", err)
+			}
+		})
+	}
+
+	// Profissionais de empresa seguem a política dela
+	if _, err := f.policies.SetProfessionalPolicy(ctx, f.professional.ID, &appointment.Policy{}); !errors.Is(err, appointment.ErrPolicyManagedByCompany) {
+		t.Fatalf("erro = %v, esperado ErrPolicyManagedByCompany", err)
+	}
+	f.setPolicy(t, &appointment.Policy{MinNoticeMinutes: 120})
+	policy, err := f.policies.GetProfessionalPolicy(ctx, f.professional.ID)
+	if err != nil {
+		t.Fatalf("GetProfessionalPolicy: %v", err)
+	}
+	if policy.MinNoticeMinutes != 120 {
+		t.Fatalf("política = %+v, esperado a da empresa", policy)
+	}
+
+	// Autônomos definem a própria política
+	independent := &user.Professional{ID: uuid.New(), UserID: uuid.New(), Name: "Dr. Bruno"}
+	if err := f.professionals.CreateProfessional(ctx, independent); err != nil {
+		t.Fatalf("CreateProfessional: %v", err)
+	}
+	if _, err := f.policies.SetProfessionalPolicy(ctx, independent.ID, &appointment.Policy{MinNoticeMinutes: 30}); err != nil {
+		t.Fatalf("SetProfessionalPolicy: %v", err)
+	}
+	policy, err = f.policies.GetProfessionalPolicy(ctx, independent.ID)
+	if err != nil {
+		t.Fatalf("GetProfessionalPolicy: %v", err)
+	}
+	if policy.MinNoticeMinutes != 30 {
+		t.Fatalf("política = %+v, esperado a do autônomo", policy)
+	}
+}
+
+func TestClientCancellationFollowsPolicy(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+
+	// Os agendamentos são no dia seguinte, dentro da antecedência de três dias
+	f.setPolicy(t, &appointment.Policy{MinNoticeMinutes: 3 * 24 * 60})
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.lifecycle.Cancel(ctx, appt.ID, client, ""); !errors.Is(err, appointment.ErrPolicyViolation) {
+		t.Fatalf("erro = %v, esperado ErrPolicyViolation", err)
+	}
+	// O profissional não está sujeito à política
+	if _, err := f.lifecycle.Cancel(ctx, appt.ID, appointment.Actor{UserID: f.professional.UserID, Provider: true}, "imprevisto"); err != nil {
+		t.Fatalf("Cancel pelo profissional: %v", err)
+	}
+
+	f.setPolicy(t, &appointment.Policy{MinNoticeMinutes: 3 * 24 * 60, AllowLateCancellation: true, LateCancellationFee: 50})
+	appt, err = f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(14, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	if _, err := f.lifecycle.Cancel(ctx, appt.ID, client, ""); err != nil {
+		t.Fatalf("Cancel tardio: %v", err)
+	}
+	stored, err := f.appointments.GetAppointmentByID(ctx, appt.ID)
+	if err != nil {
+		t.Fatalf("GetAppointmentByID: %v", err)
+	}
+	if stored.Status != appointment.StatusCancelledByClient || !stored.LateCancellation || stored.CancellationFee != 50 {
+		t.Fatalf("agendamento = %+v, esperado cancelamento tardio com taxa de 50", stored)
+	}
+}
+
+func TestClientRescheduleFollowsPolicy(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+	limit := 1
+	f.setPolicy(t, &appointment.Policy{MaxReschedules: &limit})
+
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	moved, err := f.booking.Reschedule(ctx, appt.ID, slotAt(11, 0), nil, client)
+	if err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+	if !moved.StartTime.Equal(timeAt(t, 11, 0)) || moved.RescheduleCount != 1 {
+		t.Fatalf("agendamento = %+v, esperado às 11:00 com uma remarcação", moved)
+	}
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	if _, err := f.booking.Reschedule(ctx, appt.ID, past, nil, client); !errors.Is(err, appointment.ErrInvalidStartTime) {
+		t.Fatalf("erro = %v, esperado ErrInvalidStartTime para horário passado", err)
+	}
+	if _, err := f.booking.Reschedule(ctx, appt.ID, slotAt(12, 0), nil, client); !errors.Is(err, appointment.ErrPolicyViolation) {
+		t.Fatalf("erro = %v, esperado ErrPolicyViolation após o limite", err)
+	}
+	if _, err := f.booking.Reschedule(ctx, appt.ID, slotAt(12, 0), nil, appointment.Actor{UserID: f.professional.UserID, Provider: true}); err != nil {
+		t.Fatalf("Reschedule pelo profissional: %v", err)
+	}
+}
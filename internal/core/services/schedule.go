@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// scheduleLoader carrega em lote o que define quando os profissionais atendem:
+// janelas semanais, horários extras, folgas e feriados da empresa
+type scheduleLoader struct {
+	availabilityRepo appointment.AvailabilityRepository
+	exceptionRepo    appointment.ExceptionRepository
+	profRepo         user.ProfessionalRepository
+}
+
+// professionalSchedule é a agenda de atendimento de um profissional, sem considerar os agendamentos.
+// As janelas semanais são interpretadas em loc; folgas, extras e feriados já são instantes absolutos.
+type professionalSchedule struct {
+	loc     *time.Location
+	windows map[time.Weekday][]*appointment.Availability
+	extras  []interval
+	blocks  []block
+	blocked []interval
+}
+
+// block é um período bloqueado, com o motivo exibido quando uma reserva o cruza
+type block struct {
+	interval
+	reason string
+}
+
+// load monta a agenda de cada profissional para o período [from, to)
+func (l *scheduleLoader) load(ctx context.Context, professionalIDs []uuid.UUID, from, to time.Time) (map[uuid.UUID]*professionalSchedule, error) {
+	schedules := make(map[uuid.UUID]*professionalSchedule, len(professionalIDs))
+	for _, id := range professionalIDs {
+		schedules[id] = &professionalSchedule{loc: time.UTC, windows: make(map[time.Weekday][]*appointment.Availability)}
+	}
+
+	professionals, err := l.profRepo.ListProfessionalsByIDs(ctx, professionalIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range professionals {
+		schedules[p.ID].loc = p.Location()
+	}
+
+	availabilities, err := l.availabilityRepo.ListByProfessionals(ctx, professionalIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range availabilities {
+		day, err := a.Weekday()
+		if err != nil {
+			continue
+		}
+		schedule := schedules[a.ProfessionalID]
+		schedule.windows[day] = append(schedule.windows[day], a)
+	}
+
+	exceptions, err := l.exceptionRepo.ListExceptions(ctx, professionalIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range exceptions {
+		schedule := schedules[e.ProfessionalID]
+		period := interval{start: e.StartTime, end: e.EndTime}
+		switch e.Kind {
+		case appointment.ExceptionExtraHours:
+			schedule.extras = append(schedule.extras, period)
+		case appointment.ExceptionTimeOff:
+			reason := "o profissional está ausente"
+			if e.Reason != "" {
+				reason += " (" + e.Reason + ")"
+			}
+			schedule.blocks = append(schedule.blocks, block{interval: period, reason: reason})
+		}
+	}
+
+	if err := l.loadHolidays(ctx, schedules, professionals, from, to); err != nil {
+		return nil, err
+	}
+
+	for _, schedule := range schedules {
+		schedule.extras = mergeIntervals(schedule.extras)
+		blocked := make([]interval, len(schedule.blocks))
+		for i, b := range schedule.blocks {
+			blocked[i] = b.interval
+		}
+		schedule.blocked = mergeIntervals(blocked)
+	}
+
+	return schedules, nil
+}
+
+// location retorna o fuso em que a agenda do profissional é interpretada
+func (l *scheduleLoader) location(ctx context.Context, professionalID uuid.UUID) (*time.Location, error) {
+	professionals, err := l.profRepo.ListProfessionalsByIDs(ctx, []uuid.UUID{professionalID})
+	if err != nil {
+		return nil, err
+	}
+	if len(professionals) == 0 {
+		return nil, user.ErrProfessionalNotFound
+	}
+	return professionals[0].Location(), nil
+}
+
+// loadHolidays bloqueia os feriados das empresas às quais os profissionais pertencem.
+// Cada feriado ocupa o dia inteiro no fuso da empresa.
+func (l *scheduleLoader) loadHolidays(ctx context.Context, schedules map[uuid.UUID]*professionalSchedule, professionals []*user.Professional, from, to time.Time) error {
+	byCompany := make(map[uuid.UUID][]uuid.UUID)
+	locations := make(map[uuid.UUID]*time.Location)
+	var companyIDs []uuid.UUID
+	for _, p := range professionals {
+		if p.CompanyID == nil {
+			continue
+		}
+		if _, ok := byCompany[*p.CompanyID]; !ok {
+			companyIDs = append(companyIDs, *p.CompanyID)
+			locations[*p.CompanyID] = time.UTC
+			if p.Company != nil {
+				locations[*p.CompanyID] = p.Company.Location()
+			}
+		}
+		byCompany[*p.CompanyID] = append(byCompany[*p.CompanyID], p.ID)
+	}
+	if len(companyIDs) == 0 {
+		return nil
+	}
+
+	// Um dia de folga em cada ponta cobre qualquer fuso; o que cair fora do período não afeta a busca
+	first := appointment.DateOf(from.UTC().AddDate(0, 0, -1))
+	last := appointment.DateOf(to.UTC().AddDate(0, 0, 1))
+	holidays, err := l.exceptionRepo.ListHolidays(ctx, companyIDs, first, last)
+	if err != nil {
+		return err
+	}
+	for _, h := range holidays {
+		start, end, err := h.Range(locations[h.CompanyID])
+		if err != nil {
+			continue
+		}
+		for _, id := range byCompany[h.CompanyID] {
+			schedules[id].blocks = append(schedules[id].blocks, block{
+				interval: interval{start: start, end: end},
+				reason:   "feriado: " + h.Name,
+			})
+		}
+	}
+	return nil
+}
+
+// check explica por que [start, end) não pode ser reservado, ou retorna nil se o período está aberto
+func (p *professionalSchedule) check(start, end time.Time) error {
+	for _, b := range p.blocks {
+		if start.Before(b.end) && b.start.Before(end) {
+			return fmt.Errorf("%w: %s", appointment.ErrOutsideAvailability, b.reason)
+		}
+	}
+
+	// Horários extras abrem o período independentemente das janelas semanais
+	for _, extra := range p.extras {
+		if !start.Before(extra.start) && !end.After(extra.end) {
+			return nil
+		}
+	}
+
+	local, localEnd := start.In(p.loc), end.In(p.loc)
+	day := local.Weekday()
+	var windows []string
+	for _, a := range p.windows[day] {
+		windowStart, windowEnd, err := a.Window(local)
+		if err != nil {
+			continue
+		}
+		if !start.Before(windowStart) && !end.After(windowEnd) {
+			for _, b := range a.Breaks {
+				breakStart, breakEnd, err := b.Window(local)
+				if err == nil && start.Before(breakEnd) && breakStart.Before(end) {
+					return fmt.Errorf("%w: o horário %s-%s cruza a pausa %s-%s", appointment.ErrOutsideAvailability,
+						local.Format("15:04"), localEnd.Format("15:04"), b.StartTime, b.EndTime)
+				}
+			}
+			return nil
+		}
+		windows = append(windows, a.StartTime+"-"+a.EndTime)
+	}
+
+	if len(windows) == 0 {
+		return fmt.Errorf("%w: o profissional não atende neste dia da semana (%s)", appointment.ErrOutsideAvailability, appointment.WeekdayName(day))
+	}
+	sort.Strings(windows)
+	return fmt.Errorf("%w: o horário %s-%s (%s) não cabe nas janelas de %s (%s)", appointment.ErrOutsideAvailability,
+		local.Format("15:04"), localEnd.Format("15:04"), p.loc, appointment.WeekdayName(day), strings.Join(windows, ", "))
+}
+
+// candidates lista, em ordem, os inícios em [from, to) em que o profissional atende durante todo o serviço.
+// Os inícios são alinhados ao começo de cada janela ou horário extra e avançam em tempo real, de modo que
+// numa virada de horário de verão a janela tem uma hora a menos ou a mais de horários.
+func (p *professionalSchedule) candidates(duration, granularity time.Duration, from, to time.Time) []time.Time {
+	seen := make(map[int64]bool)
+	var starts []time.Time
+	add := func(periodStart, periodEnd time.Time, pauses []interval) {
+		for start := periodStart; !start.Add(duration).After(periodEnd); start = start.Add(granularity) {
+			end := start.Add(duration)
+			if start.Before(from) {
+				continue
+			}
+			if end.After(to) {
+				break
+			}
+			if seen[start.Unix()] || overlapsAny(pauses, start, end) || overlapsAny(p.blocked, start, end) {
+				continue
+			}
+			seen[start.Unix()] = true
+			starts = append(starts, start)
+		}
+	}
+
+	// Percorre os dias do calendário local; o meio-dia nunca cai numa transição de horário
+	year, month, date := from.In(p.loc).Date()
+	for i := 0; appointment.LocalTime(year, month, date+i, 0, 0, p.loc).Before(to); i++ {
+		day := time.Date(year, month, date+i, 12, 0, 0, 0, p.loc)
+		for _, a := range p.windows[day.Weekday()] {
+			windowStart, windowEnd, err := a.Window(day)
+			if err != nil {
+				continue
+			}
+			add(windowStart, windowEnd, breakIntervals(a, day))
+		}
+	}
+	for _, extra := range p.extras {
+		add(extra.start, extra.end, nil)
+	}
+
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts
+}
+
+// breakIntervals retorna as pausas da janela no dia informado, ordenadas e disjuntas
+func breakIntervals(a *appointment.Availability, day time.Time) []interval {
+	var pauses []interval
+	for i := range a.Breaks {
+		start, end, err := a.Breaks[i].Window(day)
+		if err != nil {
+			continue
+		}
+		pauses = append(pauses, interval{start: start, end: end})
+	}
+	return mergeIntervals(pauses)
+}
+
+// interval representa um período [start, end)
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// padded estende o período em d antes e depois
+func (i interval) padded(d time.Duration) interval {
+	return interval{start: i.start.Add(-d), end: i.end.Add(d)}
+}
+
+// mergeIntervals ordena e une intervalos sobrepostos, permitindo busca binária em overlapsAny
+func mergeIntervals(intervals []interval) []interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	merged := []interval{intervals[0]}
+	for _, current := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if current.start.After(last.end) {
+			merged = append(merged, current)
+			continue
+		}
+		if current.end.After(last.end) {
+			last.end = current.end
+		}
+	}
+	return merged
+}
+
+// overlapsAny indica se [start, end) cruza algum dos intervalos ordenados e disjuntos
+func overlapsAny(intervals []interval, start, end time.Time) bool {
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].end.After(start) })
+	return i < len(intervals) && intervals[i].start.Before(end)
+}
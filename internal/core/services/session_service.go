@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// SessionService consulta as sessões dos serviços em grupo
+type SessionService struct {
+	appointmentRepo appointment.Repository
+	holdRepo        appointment.HoldRepository
+	serviceRepo     service.Repository
+	userRepo        user.UserRepository
+}
+
+func NewSessionService(appointmentRepo appointment.Repository, holdRepo appointment.HoldRepository, serviceRepo service.Repository, userRepo user.UserRepository) *SessionService {
+	return &SessionService{
+		appointmentRepo: appointmentRepo,
+		holdRepo:        holdRepo,
+		serviceRepo:     serviceRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// Attendees retorna a sessão do agendamento, com as vagas restantes e os clientes em ordem de reserva
+func (s *SessionService) Attendees(ctx context.Context, appointmentID uuid.UUID) (*appointment.Session, error) {
+	appt, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := s.serviceRepo.GetServiceByID(ctx, appt.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.appointmentRepo.ListActiveInRange(ctx, []uuid.UUID{appt.ProfessionalID}, appt.StartTime, appt.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	var members []*appointment.Appointment
+	var clientIDs []uuid.UUID
+	for _, a := range active {
+		if a.InSession(svc.ID, appt.StartTime) {
+			members = append(members, a)
+			clientIDs = append(clientIDs, a.ClientID)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].CreatedAt.Before(members[j].CreatedAt) })
+
+	// Vagas reservadas temporariamente não estão livres, mas ainda não têm participante
+	holds, err := s.holdRepo.ListActiveHolds(ctx, []uuid.UUID{appt.ProfessionalID}, appt.StartTime, appt.EndTime, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	held := 0
+	for _, h := range holds {
+		if h.ServiceID == svc.ID && h.StartTime.Equal(appt.StartTime) {
+			held++
+		}
+	}
+
+	clients := make(map[uuid.UUID]*user.User)
+	if len(clientIDs) > 0 {
+		users, err := s.userRepo.ListByIDs(ctx, clientIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			clients[u.ID] = u
+		}
+	}
+
+	session := &appointment.Session{
+		ProfessionalID: appt.ProfessionalID,
+		ServiceID:      svc.ID,
+		StartTime:      appt.StartTime,
+		EndTime:        appt.EndTime,
+		Capacity:       svc.Seats(),
+		Attendees:      []appointment.Attendee{},
+	}
+	if free := svc.Seats() - len(members) - held; free > 0 {
+		session.SeatsAvailable = free
+	}
+	for _, a := range members {
+		attendee := appointment.Attendee{
+			AppointmentID: a.ID,
+			ClientID:      a.ClientID,
+			Status:        a.Status,
+			BookedAt:      a.CreatedAt,
+		}
+		if client, ok := clients[a.ClientID]; ok {
+			attendee.Name = client.Name
+			attendee.Email = client.Email
+		}
+		session.Attendees = append(session.Attendees, attendee)
+	}
+	return session, nil
+}
+
+// occupant é um agendamento ativo ou uma reserva temporária ocupando a agenda do profissional
+type occupant struct {
+	id             uuid.UUID
+	professionalID uuid.UUID
+	serviceID      uuid.UUID
+	clientID       uuid.UUID
+	start          time.Time
+	end            time.Time
+	hold           bool
+}
+
+// listOccupants retorna os agendamentos ativos e as reservas ainda válidas dos profissionais em [from, to)
+func listOccupants(ctx context.Context, appointmentRepo appointment.Repository, holdRepo appointment.HoldRepository, professionalIDs []uuid.UUID, from, to time.Time) ([]occupant, error) {
+	appts, err := appointmentRepo.ListActiveInRange(ctx, professionalIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := holdRepo.ListActiveHolds(ctx, professionalIDs, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	occupants := make([]occupant, 0, len(appts)+len(holds))
+	for _, a := range appts {
+		occupants = append(occupants, occupant{id: a.ID, professionalID: a.ProfessionalID, serviceID: a.ServiceID, clientID: a.ClientID, start: a.StartTime, end: a.EndTime})
+	}
+	for _, h := range holds {
+		occupants = append(occupants, occupant{id: h.ID, professionalID: h.ProfessionalID, serviceID: h.ServiceID, clientID: h.ClientID, start: h.StartTime, end: h.EndTime, hold: true})
+	}
+	return occupants, nil
+}
+
+// checkSeat explica por que o cliente não pode ocupar period com o profissional, dados os agendamentos ativos
+// e as reservas temporárias em existing (os de skip são ignorados). Cada ocupante exige buffer livre antes e
+// depois, e existing deve cobrir period estendido por buffer. Em serviços individuais qualquer sobreposição
+// conflita; em serviços em grupo o cliente entra na sessão do mesmo serviço e início enquanto houver vagas, e cada
+// reserva ocupa uma vaga. Deve ser chamada com a agenda do profissional bloqueada, para que reservas simultâneas
+// não ocupem a mesma vaga.
+func checkSeat(existing []occupant, svc *service.Service, clientID uuid.UUID, period interval, buffer time.Duration, skip map[uuid.UUID]bool) error {
+	taken := 0
+	for _, e := range existing {
+		if skip[e.id] || !e.start.Add(-buffer).Before(period.end) || !period.start.Before(e.end.Add(buffer)) {
+			continue
+		}
+		if !svc.IsGroup() || e.serviceID != svc.ID || !e.start.Equal(period.start) {
+			if e.hold {
+				return appointment.ErrSlotHeld
+			}
+			return appointment.ErrConflict
+		}
+		if e.clientID == clientID {
+			return appointment.ErrAlreadyAttending
+		}
+		taken++
+	}
+	if taken >= svc.Seats() {
+		return appointment.ErrSessionFull
+	}
+	return nil
+}
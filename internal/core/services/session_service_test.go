@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/user"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+func TestConcurrentBookingsFillGroupSessionWithoutOverbooking(t *testing.T) {
+	const seats = 4
+	f := newBookingFixture(t)
+	svc := f.newService(t, seats)
+
+	// Um cliente a mais do que as vagas, todos ao mesmo tempo
+	starts := make([]string, seats+1)
+	for i := range starts {
+		starts[i] = slotAt(9, 0)
+	}
+	errs := f.bookConcurrently(svc, starts)
+
+	booked, full := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case errors.Is(err, appointment.ErrSessionFull):
+			full++
+		default:
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if booked != seats || full != 1 {
+		t.Fatalf("%d reservas aceitas e %d recusadas por lotação, esperado %d e 1", booked, full, seats)
+	}
+
+	appts, err := f.appointments.ListByProfessional(context.Background(), f.professional.ID)
+	if err != nil {
+		t.Fatalf("ListByProfessional: %v", err)
+	}
+	if len(appts) != seats {
+		t.Fatalf("%d agendamentos gravados para a sessão, esperado %d", len(appts), seats)
+	}
+}
+
+func TestGroupSessionRejectsOverlappingStart(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 3)
+
+	if errs := f.bookConcurrently(svc, []string{slotAt(9, 0)}); errs[0] != nil {
+		t.Fatalf("primeira reserva: %v", errs[0])
+	}
+	// Outro início do mesmo serviço é outra sessão e não pode cruzar a primeira
+	if errs := f.bookConcurrently(svc, []string{slotAt(9, 30)}); !errors.Is(errs[0], appointment.ErrConflict) {
+		t.Fatalf("erro = %v, esperado %v", errs[0], appointment.ErrConflict)
+	}
+}
+
+func TestGroupSessionListsAttendees(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 3)
+	sessions := services.NewSessionService(f.appointments, f.holds, f.services, f.users)
+
+	var first *appointment.Appointment
+	for _, name := range []string{"Ana", "Bruno"} {
+		client := &user.User{ID: uuid.New(), Name: name, Email: name + "@example.com", Role: "client"}
+		if err := f.users.Create(ctx, client); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.ID, slotAt(9, 0), nil)
+		if err != nil {
+			t.Fatalf("BookAppointment: %v", err)
+		}
+		if first == nil {
+			first = appt
+		}
+	}
+
+	// O mesmo cliente não ocupa duas vagas da sessão
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, first.ClientID, slotAt(9, 0), nil); !errors.Is(err, appointment.ErrAlreadyAttending) {
+		t.Fatalf("erro = %v, esperado ErrAlreadyAttending", err)
+	}
+
+	session, err := sessions.Attendees(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Attendees: %v", err)
+	}
+	if session.Capacity != 3 || session.SeatsAvailable != 1 || len(session.Attendees) != 2 {
+		t.Fatalf("sessão = %+v, esperado 2 de 3 vagas ocupadas", session)
+	}
+	if session.Attendees[0].Name != "Ana" || session.Attendees[1].Name != "Bruno" {
+		t.Fatalf("participantes = %+v, esperado Ana e Bruno em ordem de reserva", session.Attendees)
+	}
+}
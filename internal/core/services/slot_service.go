@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+const defaultSlotSearchRange = 7 * 24 * time.Hour
+
+type SlotSettings struct {
+	// Granularity é o passo entre inícios de horários consecutivos
+	Granularity time.Duration
+	// Buffer é o intervalo livre exigido antes e depois de cada agendamento
+	Buffer time.Duration
+	// MaxRange limita o período de uma busca
+	MaxRange time.Duration
+}
+
+// sessionKey identifica uma sessão de serviço em grupo pelo profissional e pelo início
+type sessionKey struct {
+	professionalID uuid.UUID
+	start          int64
+}
+
+// SlotService calcula os horários livres a partir da disponibilidade semanal e dos agendamentos
+type SlotService struct {
+	serviceRepo     service.Repository
+	appointmentRepo appointment.Repository
+	holdRepo        appointment.HoldRepository
+	schedules       *scheduleLoader
+	settings        SlotSettings
+}
+
+func NewSlotService(serviceRepo service.Repository, appointmentRepo appointment.Repository, holdRepo appointment.HoldRepository, availabilityRepo appointment.AvailabilityRepository, exceptionRepo appointment.ExceptionRepository, profRepo user.ProfessionalRepository, settings SlotSettings) *SlotService {
+	return &SlotService{
+		serviceRepo:     serviceRepo,
+		appointmentRepo: appointmentRepo,
+		holdRepo:        holdRepo,
+		schedules: &scheduleLoader{
+			availabilityRepo: availabilityRepo,
+			exceptionRepo:    exceptionRepo,
+			profRepo:         profRepo,
+		},
+		settings: settings,
+	}
+}
+
+// FindSlots lista os inícios livres do serviço em [from, to), de todos os profissionais que o atendem
+// ou apenas de professionalID. from vazio significa agora; to vazio, uma semana depois de from.
+func (s *SlotService) FindSlots(ctx context.Context, serviceID uuid.UUID, professionalID *uuid.UUID, from, to time.Time) ([]*appointment.Slot, error) {
+	now := time.Now()
+	if from.IsZero() {
+		from = now
+	}
+	if to.IsZero() {
+		to = from.Add(defaultSlotSearchRange)
+	}
+	if !from.Before(to) || to.Sub(from) > s.settings.MaxRange {
+		return nil, appointment.ErrInvalidRange
+	}
+	// Horários passados não podem ser reservados
+	if from.Before(now) {
+		from = now
+	}
+
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	professionalIDs := []uuid.UUID(svc.ProfessionalIDs)
+	if professionalID != nil {
+		if !svc.OfferedBy(*professionalID) {
+			return nil, service.ErrProfessionalNotOffering
+		}
+		professionalIDs = []uuid.UUID{*professionalID}
+	}
+
+	slots := []*appointment.Slot{}
+	if len(professionalIDs) == 0 || !from.Before(to) {
+		return slots, nil
+	}
+
+	// Poucas consultas para todos os profissionais; o restante é calculado em memória
+	schedules, err := s.schedules.load(ctx, professionalIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reservas temporárias ocupam a agenda como agendamentos
+	occupants, err := listOccupants(ctx, s.appointmentRepo, s.holdRepo, professionalIDs, from.Add(-s.settings.Buffer), to.Add(s.settings.Buffer))
+	if err != nil {
+		return nil, err
+	}
+
+	// Em serviços em grupo, os agendamentos de uma sessão contam vagas e ocupam a agenda uma única vez
+	busy := make(map[uuid.UUID][]interval)
+	taken := make(map[sessionKey]int)
+	for _, o := range occupants {
+		if svc.IsGroup() && o.serviceID == svc.ID {
+			key := sessionKey{professionalID: o.professionalID, start: o.start.Unix()}
+			if taken[key]++; taken[key] > 1 {
+				continue
+			}
+		}
+		busy[o.professionalID] = append(busy[o.professionalID], interval{
+			start: o.start.Add(-s.settings.Buffer),
+			end:   o.end.Add(s.settings.Buffer),
+		})
+	}
+
+	duration := time.Duration(svc.Duration) * time.Minute
+	for _, id := range professionalIDs {
+		occupied := mergeIntervals(busy[id])
+		for _, start := range schedules[id].candidates(duration, s.settings.Granularity, from, to) {
+			end := start.Add(duration)
+			slot := &appointment.Slot{
+				ProfessionalID: id,
+				StartTime:      start,
+				EndTime:        end,
+			}
+			if svc.IsGroup() {
+				// Uma sessão já iniciada neste horário aceita novos clientes enquanto tiver vagas
+				booked := taken[sessionKey{professionalID: id, start: start.Unix()}]
+				if booked >= svc.Seats() {
+					continue
+				}
+				slot.SeatsAvailable = svc.Seats() - booked
+				if booked > 0 {
+					slots = append(slots, slot)
+					continue
+				}
+			}
+			if overlapsAny(occupied, start, end) {
+				continue
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		if !slots[i].StartTime.Equal(slots[j].StartTime) {
+			return slots[i].StartTime.Before(slots[j].StartTime)
+		}
+		return slots[i].ProfessionalID.String() < slots[j].ProfessionalID.String()
+	})
+	return slots, nil
+}
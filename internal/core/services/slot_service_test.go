@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/services"
+
+	"github.com/google/uuid"
+)
+
+func (f *bookingFixture) newSlotService(buffer time.Duration) *services.SlotService {
+	return services.NewSlotService(f.services, f.appointments, f.holds, f.availability, f.exceptions, f.professionals, services.SlotSettings{
+		Granularity: 30 * time.Minute,
+		Buffer:      buffer,
+		MaxRange:    7 * 24 * time.Hour,
+	})
+}
+
+// timeAt interpreta um horário gerado por slotAt
+func timeAt(t *testing.T, hour, minute int) time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, slotAt(hour, minute))
+	if err != nil {
+		t.Fatalf("time.Parse: %v", err)
+	}
+	return at
+}
+
+func TestFindSlotsSkipsAppointmentsAndBuffer(t *testing.T) {
+	f := newBookingFixtureWithSettings(t, services.BookingSettings{Buffer: 15 * time.Minute})
+	svc := f.newService(t, 1)
+	if errs := f.bookConcurrently(svc, []string{slotAt(10, 0)}); errs[0] != nil {
+		t.Fatalf("BookAppointment: %v", errs[0])
+	}
+
+	slots, err := f.newSlotService(15*time.Minute).FindSlots(context.Background(), svc.ID, nil, timeAt(t, 7, 0), timeAt(t, 13, 0))
+	if err != nil {
+		t.Fatalf("FindSlots: %v", err)
+	}
+
+	// A janela começa às 08:00 e o agendamento ocupa das 09:45 às 11:15 com o buffer
+	want := []time.Time{timeAt(t, 8, 0), timeAt(t, 8, 30), timeAt(t, 11, 30), timeAt(t, 12, 0)}
+	if len(slots) != len(want) {
+		t.Fatalf("%d horários livres, esperado %d: %+v", len(slots), len(want), slots)
+	}
+	for i, slot := range slots {
+		if !slot.StartTime.Equal(want[i]) || slot.EndTime.Sub(slot.StartTime) != time.Hour || slot.ProfessionalID != f.professional.ID {
+			t.Fatalf("horário %d = %+v, esperado início %s com uma hora de duração", i, slot, want[i])
+		}
+	}
+}
+
+func TestFindSlotsRejectsInvalidSearches(t *testing.T) {
+	f := newBookingFixture(t)
+	svc := f.newService(t, 1)
+	slotService := f.newSlotService(0)
+	otherProfessional := uuid.New()
+
+	tests := []struct {
+		name           string
+		serviceID      uuid.UUID
+		professionalID *uuid.UUID
+		from, to       time.Time
+		want           error
+	}{
+		{"fim antes do início", svc.ID, nil, timeAt(t, 12, 0), timeAt(t, 8, 0), appointment.ErrInvalidRange},
+		{"período acima do limite", svc.ID, nil, timeAt(t, 8, 0), timeAt(t, 8, 0).AddDate(0, 0, 8), appointment.ErrInvalidRange},
+		{"serviço inexistente", uuid.New(), nil, timeAt(t, 8, 0), timeAt(t, 12, 0), service.ErrNotFound},
+		{"profissional fora do serviço", svc.ID, &otherProfessional, timeAt(t, 8, 0), timeAt(t, 12, 0), service.ErrProfessionalNotOffering},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := slotService.FindSlots(context.Background(), tt.serviceID, tt.professionalID, tt.from, tt.to)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
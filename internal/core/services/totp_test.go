@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// Vetores do apêndice B da RFC 6238 (SHA1), truncados para 6 dígitos
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(key, uint64(tt.unix/totpPeriod)); got != tt.want {
+			t.Errorf("totpCode(%d) = %s, esperado %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := newTOTPSecret()
+	if err != nil {
+		t.Fatalf("newTOTPSecret: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	step := now.Unix() / totpPeriod
+	codeAt := func(offset int64) string {
+		key, _ := totpEncoding.DecodeString(secret)
+		return totpCode(key, uint64(step+offset))
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		want     bool
+	}{
+		{"passo atual", codeAt(0), 0, true},
+		{"passo anterior", codeAt(-1), 0, true},
+		{"próximo passo", codeAt(1), 0, true},
+		{"fora da tolerância", codeAt(2), 0, false},
+		{"código já usado", codeAt(0), step, false},
+		{"código antigo após um mais novo", codeAt(-1), step, false},
+		{"código errado", "000000", 0, codeAt(-1) == "000000" || codeAt(0) == "000000" || codeAt(1) == "000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := validateTOTP(secret, tt.code, now, tt.lastStep); ok != tt.want {
+				t.Fatalf("validateTOTP = %v, esperado %v", ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/notification"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistSettings define o prazo para aceitar um horário oferecido, a base do link enviado por email e o
+// intervalo livre exigido entre agendamentos, o mesmo das reservas
+type WaitlistSettings struct {
+	OfferTTL    time.Duration
+	LinkBaseURL string
+	Buffer      time.Duration
+}
+
+// WaitlistService mantém a lista de espera: quando um horário é liberado, ele fica reservado para o
+// primeiro cliente da fila por OfferTTL e, se a oferta expirar, passa ao próximo
+type WaitlistService struct {
+	waitlistRepo    appointment.WaitlistRepository
+	holdRepo        appointment.HoldRepository
+	appointmentRepo appointment.Repository
+	serviceRepo     service.Repository
+	userRepo        user.UserRepository
+	booking         *BookingService
+	sender          notification.EmailSender
+	tx              appointment.TransactionManager
+	settings        WaitlistSettings
+}
+
+func NewWaitlistService(waitlistRepo appointment.WaitlistRepository, holdRepo appointment.HoldRepository, appointmentRepo appointment.Repository, serviceRepo service.Repository, userRepo user.UserRepository, booking *BookingService, sender notification.EmailSender, tx appointment.TransactionManager, settings WaitlistSettings) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo:    waitlistRepo,
+		holdRepo:        holdRepo,
+		appointmentRepo: appointmentRepo,
+		serviceRepo:     serviceRepo,
+		userRepo:        userRepo,
+		booking:         booking,
+		sender:          sender,
+		tx:              tx,
+		settings:        settings,
+	}
+}
+
+// Join coloca o cliente na fila por um horário do serviço com o profissional entre fromStr e toStr (RFC3339)
+func (s *WaitlistService) Join(ctx context.Context, clientID, serviceID, professionalID uuid.UUID, fromStr, toStr string) (*appointment.WaitlistEntry, error) {
+	from, errFrom := time.Parse(time.RFC3339, fromStr)
+	to, errTo := time.Parse(time.RFC3339, toStr)
+	if errFrom != nil || errTo != nil {
+		return nil, fmt.Errorf("%w: from e to devem estar no formato RFC3339", appointment.ErrInvalidWaitlist)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from deve ser anterior a to", appointment.ErrInvalidWaitlist)
+	}
+	if !to.After(time.Now()) {
+		return nil, fmt.Errorf("%w: o período já passou", appointment.ErrInvalidWaitlist)
+	}
+
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.OfferedBy(professionalID) {
+		return nil, service.ErrProfessionalNotOffering
+	}
+	if to.Sub(from) < time.Duration(svc.Duration)*time.Minute {
+		return nil, fmt.Errorf("%w: o período é menor que a duração do serviço", appointment.ErrInvalidWaitlist)
+	}
+
+	open, err := s.waitlistRepo.HasOpenEntry(ctx, clientID, professionalID, serviceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, appointment.ErrAlreadyWaitlisted
+	}
+
+	entry := &appointment.WaitlistEntry{
+		ID:             uuid.New(),
+		ClientID:       clientID,
+		ProfessionalID: professionalID,
+		ServiceID:      serviceID,
+		From:           from.UTC(),
+		To:             to.UTC(),
+		Status:         appointment.WaitlistWaiting,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.waitlistRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetEntry retorna a entrada com a oferta em aberto, se houver
+func (s *WaitlistService) GetEntry(ctx context.Context, id uuid.UUID) (*appointment.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return entry, s.attachOffers(ctx, entry)
+}
+
+// ListByClient retorna as entradas do cliente em ordem de chegada
+func (s *WaitlistService) ListByClient(ctx context.Context, clientID uuid.UUID) ([]*appointment.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.ListByClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return entries, s.attachOffers(ctx, entries...)
+}
+
+// ListByProfessional retorna a fila do profissional em ordem de chegada
+func (s *WaitlistService) ListByProfessional(ctx context.Context, professionalID uuid.UUID) ([]*appointment.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.ListByProfessional(ctx, professionalID)
+	if err != nil {
+		return nil, err
+	}
+	return entries, s.attachOffers(ctx, entries...)
+}
+
+// Accept agenda o horário oferecido à entrada, consumindo a reserva
+func (s *WaitlistService) Accept(ctx context.Context, id uuid.UUID) (*appointment.Appointment, error) {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != appointment.WaitlistOffered || entry.HoldID == nil {
+		return nil, fmt.Errorf("%w: a entrada está com status %s", appointment.ErrNoOffer, entry.Status)
+	}
+
+	appt, err := s.booking.ConvertHold(ctx, *entry.HoldID, entry.ClientID)
+	if errors.Is(err, appointment.ErrHoldNotFound) {
+		return nil, appointment.ErrHoldExpired
+	}
+	return appt, err
+}
+
+// Leave tira o cliente da fila. Um horário que estava reservado para ele passa ao próximo da fila.
+func (s *WaitlistService) Leave(ctx context.Context, id uuid.UUID) error {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var next *waitlistOffer
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Serializa com as ofertas e a expiração das reservas do profissional
+		if err := s.appointmentRepo.LockProfessionalSchedule(ctx, entry.ProfessionalID); err != nil {
+			return err
+		}
+		current, err := s.waitlistRepo.GetEntryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		var released *appointment.Hold
+		switch current.Status {
+		case appointment.WaitlistWaiting:
+		case appointment.WaitlistOffered:
+			if released, err = s.releaseHold(ctx, current); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: a entrada já está com status %s", appointment.ErrInvalidWaitlist, current.Status)
+		}
+
+		current.Status = appointment.WaitlistLeft
+		if err := s.waitlistRepo.UpdateEntry(ctx, current); err != nil || released == nil {
+			return err
+		}
+		next, err = s.offer(ctx, released.ProfessionalID, released.ServiceID, released.StartTime.UTC())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.notify(ctx, next); err != nil {
+		log.Printf("waitlist: %v", err)
+	}
+	return nil
+}
+
+// slotFreed reserva o horário do agendamento cancelado para o primeiro cliente da fila que pode ocupá-lo.
+// Deve ser chamada na transação que liberou o horário, com a agenda do profissional bloqueada; o aviso por
+// email da oferta retornada é enviado com notify depois da confirmação.
+func (s *WaitlistService) slotFreed(ctx context.Context, appt *appointment.Appointment) (*waitlistOffer, error) {
+	return s.offer(ctx, appt.ProfessionalID, appt.ServiceID, appt.StartTime.UTC())
+}
+
+// ExpireHolds apaga as reservas vencidas e, na mesma transação, oferece cada horário liberado ao próximo da fila.
+// Ofertas da lista de espera que expiraram são marcadas como vencidas.
+func (s *WaitlistService) ExpireHolds(ctx context.Context) error {
+	expired, err := s.holdRepo.ListExpiredHolds(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, hold := range expired {
+		var next *waitlistOffer
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.appointmentRepo.LockProfessionalSchedule(ctx, hold.ProfessionalID); err != nil {
+				return err
+			}
+			// A reserva pode ter sido convertida em agendamento enquanto a agenda não estava bloqueada
+			if _, err := s.holdRepo.GetHoldByID(ctx, hold.ID); errors.Is(err, appointment.ErrHoldNotFound) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err := s.holdRepo.DeleteHold(ctx, hold.ID); err != nil {
+				return err
+			}
+
+			if hold.WaitlistEntryID != nil {
+				entry, err := s.waitlistRepo.GetEntryByID(ctx, *hold.WaitlistEntryID)
+				if err != nil {
+					return err
+				}
+				if entry.Status == appointment.WaitlistOffered {
+					entry.Status = appointment.WaitlistExpired
+					if err := s.waitlistRepo.UpdateEntry(ctx, entry); err != nil {
+						return err
+					}
+				}
+			}
+
+			var err error
+			next, err = s.offer(ctx, hold.ProfessionalID, hold.ServiceID, hold.StartTime.UTC())
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reserva %s: %w", hold.ID, err))
+			continue
+		}
+		if err := s.notify(ctx, next); err != nil {
+			errs = append(errs, fmt.Errorf("reserva %s: %w", hold.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitlistOffer é um horário reservado para uma entrada da lista de espera, ainda sem o aviso ao cliente
+type waitlistOffer struct {
+	svc   *service.Service
+	entry *appointment.WaitlistEntry
+	hold  *appointment.Hold
+}
+
+// offer reserva o horário que começa em start para a primeira entrada da fila que pode ocupá-lo. Deve ser
+// chamada em uma transação com a agenda do profissional bloqueada. Nada é oferecido se o horário já passou
+// ou continua ocupado.
+func (s *WaitlistService) offer(ctx context.Context, professionalID, serviceID uuid.UUID, start time.Time) (*waitlistOffer, error) {
+	if !start.After(time.Now()) {
+		return nil, nil
+	}
+	svc, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	period := interval{start: start, end: start.Add(time.Duration(svc.Duration) * time.Minute)}
+
+	waiting, err := s.waitlistRepo.ListWaiting(ctx, professionalID, serviceID, period.start, period.end)
+	if err != nil || len(waiting) == 0 {
+		return nil, err
+	}
+	search := period.padded(s.settings.Buffer)
+	existing, err := listOccupants(ctx, s.appointmentRepo, s.holdRepo, []uuid.UUID{professionalID}, search.start, search.end)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range waiting {
+		err := checkSeat(existing, svc, candidate.ClientID, period, s.settings.Buffer, nil)
+		if errors.Is(err, appointment.ErrAlreadyAttending) {
+			continue
+		}
+		if err != nil {
+			// O horário foi ocupado por outra reserva
+			return nil, nil
+		}
+
+		now := time.Now().UTC()
+		hold := &appointment.Hold{
+			ID:              uuid.New(),
+			ProfessionalID:  professionalID,
+			ServiceID:       serviceID,
+			ClientID:        candidate.ClientID,
+			StartTime:       period.start,
+			EndTime:         period.end,
+			ExpiresAt:       now.Add(s.settings.OfferTTL),
+			WaitlistEntryID: &candidate.ID,
+			CreatedAt:       now,
+		}
+		if err := s.holdRepo.CreateHold(ctx, hold); err != nil {
+			return nil, err
+		}
+		candidate.Status = appointment.WaitlistOffered
+		candidate.HoldID = &hold.ID
+		if err := s.waitlistRepo.UpdateEntry(ctx, candidate); err != nil {
+			return nil, err
+		}
+		return &waitlistOffer{svc: svc, entry: candidate, hold: hold}, nil
+	}
+	return nil, nil
+}
+
+// notify avisa por email os clientes das ofertas, depois de confirmada a transação que as criou. Uma falha
+// no envio não desfaz a oferta, que continua visível em GET /waitlist/{id} até expirar.
+func (s *WaitlistService) notify(ctx context.Context, offers ...*waitlistOffer) error {
+	var errs []error
+	for _, offer := range offers {
+		if offer == nil {
+			continue
+		}
+		if err := s.notifyOffer(ctx, offer.svc, offer.entry, offer.hold); err != nil {
+			errs = append(errs, fmt.Errorf("falha ao avisar a entrada %s: %w", offer.entry.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// releaseHold apaga a reserva oferecida à entrada, retornando-a para que o horário siga na fila
+func (s *WaitlistService) releaseHold(ctx context.Context, entry *appointment.WaitlistEntry) (*appointment.Hold, error) {
+	if entry.HoldID == nil {
+		return nil, nil
+	}
+	hold, err := s.holdRepo.GetHoldByID(ctx, *entry.HoldID)
+	if errors.Is(err, appointment.ErrHoldNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hold, s.holdRepo.DeleteHold(ctx, hold.ID)
+}
+
+func (s *WaitlistService) notifyOffer(ctx context.Context, svc *service.Service, entry *appointment.WaitlistEntry, hold *appointment.Hold) error {
+	client, err := s.userRepo.GetByID(ctx, entry.ClientID)
+	if err != nil {
+		return err
+	}
+	loc, err := s.booking.schedules.location(ctx, hold.ProfessionalID)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &notification.Email{
+		To:      client.Email,
+		Subject: "Um horário da sua lista de espera ficou livre no YouMeet",
+		Body: fmt.Sprintf("Olá, %s!\n\nO horário de %s em %s está reservado para você até %s.\n\nPara agendar, acesse o link abaixo:\n%s\n\nDepois disso, o horário será oferecido ao próximo cliente da lista de espera.",
+			client.Name, svc.Name, hold.StartTime.In(loc).Format("02/01/2006 15:04"), hold.ExpiresAt.In(loc).Format("02/01/2006 15:04"),
+			s.settings.LinkBaseURL+"/waitlist/"+entry.ID.String()),
+	})
+}
+
+// attachOffers preenche a oferta das entradas que têm um horário reservado
+func (s *WaitlistService) attachOffers(ctx context.Context, entries ...*appointment.WaitlistEntry) error {
+	for _, entry := range entries {
+		if entry.Status != appointment.WaitlistOffered || entry.HoldID == nil {
+			continue
+		}
+		hold, err := s.holdRepo.GetHoldByID(ctx, *entry.HoldID)
+		if errors.Is(err, appointment.ErrHoldNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		entry.Offer = hold
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/appointment"
+	"youmeet/internal/core/domain/service"
+	"youmeet/internal/core/domain/user"
+
+	"github.com/google/uuid"
+)
+
+// joinWaitlist cadastra um cliente e o coloca na fila do serviço para a manhã de amanhã
+func (f *bookingFixture) joinWaitlist(t *testing.T, svc *service.Service, name string) *appointment.WaitlistEntry {
+	t.Helper()
+	ctx := context.Background()
+	client := &user.User{ID: uuid.New(), Name: name, Email: strings.ToLower(name) + "@example.com", Role: "client"}
+	if err := f.users.Create(ctx, client); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	entry, err := f.waitlists.Join(ctx, client.ID, svc.ID, f.professional.ID, slotAt(8, 0), slotAt(12, 0))
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	return entry
+}
+
+// expireOffer antecipa o vencimento da oferta em aberto da entrada
+func (f *bookingFixture) expireOffer(t *testing.T, entryID uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	entry, err := f.waitlists.GetEntry(ctx, entryID)
+	if err != nil || entry.Offer == nil {
+		t.Fatalf("GetEntry = %+v, %v, esperado uma oferta", entry, err)
+	}
+	if err := f.holds.ExpireHold(ctx, entry.Offer.ID, time.Now()); err != nil {
+		t.Fatalf("ExpireHold: %v", err)
+	}
+}
+
+func TestCancellationOffersSlotToWaitlist(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	first, second := f.joinWaitlist(t, svc, "Bruno"), f.joinWaitlist(t, svc, "Carla")
+
+	if _, err := f.lifecycle.Cancel(ctx, appt.ID, client, ""); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	entry, err := f.waitlists.GetEntry(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetEntry: %v", err)
+	}
+	if entry.Status != appointment.WaitlistOffered || entry.Offer == nil || !entry.Offer.StartTime.Equal(timeAt(t, 10, 0)) {
+		t.Fatalf("entrada = %+v, esperado oferta às 10:00", entry)
+	}
+	emails := f.outbox.sent()
+	if len(emails) != 1 || emails[0].To != "bruno@example.com" || !strings.Contains(emails[0].Body, "/waitlist/"+first.ID.String()) {
+		t.Fatalf("emails = %+v, esperado um aviso para o primeiro da fila", emails)
+	}
+
+	// Enquanto a oferta vale, o horário não aceita outras reservas
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); !errors.Is(err, appointment.ErrSlotHeld) {
+		t.Fatalf("erro = %v, esperado ErrSlotHeld", err)
+	}
+	// Apenas o dono da entrada recebe a oferta
+	if _, err := f.booking.ConvertHold(ctx, *entry.HoldID, second.ClientID); !errors.Is(err, appointment.ErrHoldNotFound) {
+		t.Fatalf("erro = %v, esperado ErrHoldNotFound", err)
+	}
+
+	booked, err := f.waitlists.Accept(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if booked.ClientID != first.ClientID || !booked.StartTime.Equal(timeAt(t, 10, 0)) {
+		t.Fatalf("agendamento = %+v, esperado do primeiro da fila às 10:00", booked)
+	}
+	if entry, _ = f.waitlists.GetEntry(ctx, first.ID); entry.Status != appointment.WaitlistBooked {
+		t.Fatalf("entrada com status %s, esperado %s", entry.Status, appointment.WaitlistBooked)
+	}
+	if entry, _ = f.waitlists.GetEntry(ctx, second.ID); entry.Status != appointment.WaitlistWaiting {
+		t.Fatalf("entrada com status %s, esperado %s", entry.Status, appointment.WaitlistWaiting)
+	}
+}
+
+func TestExpiredOfferPassesToNextInLine(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+	svc := f.newService(t, 1)
+	client := appointment.Actor{UserID: uuid.New()}
+	appt, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, client.UserID, slotAt(10, 0), nil)
+	if err != nil {
+		t.Fatalf("BookAppointment: %v", err)
+	}
+	first, second := f.joinWaitlist(t, svc, "Bruno"), f.joinWaitlist(t, svc, "Carla")
+	if _, err := f.lifecycle.Cancel(ctx, appt.ID, client, ""); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	f.expireOffer(t, first.ID)
+	if _, err := f.waitlists.Accept(ctx, first.ID); !errors.Is(err, appointment.ErrHoldExpired) {
+		t.Fatalf("erro = %v, esperado ErrHoldExpired", err)
+	}
+	if err := f.waitlists.ExpireHolds(ctx); err != nil {
+		t.Fatalf("ExpireHolds: %v", err)
+	}
+
+	entry, err := f.waitlists.GetEntry(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetEntry: %v", err)
+	}
+	if entry.Status != appointment.WaitlistExpired || entry.Offer != nil {
+		t.Fatalf("entrada = %+v, esperado oferta vencida", entry)
+	}
+	if entry, err = f.waitlists.GetEntry(ctx, second.ID); err != nil || entry.Status != appointment.WaitlistOffered {
+		t.Fatalf("entrada = %+v, %v, esperado oferta ao próximo da fila", entry, err)
+	}
+	emails := f.outbox.sent()
+	if len(emails) != 2 || emails[1].To != "carla@example.com" {
+		t.Fatalf("emails = %+v, esperado o segundo aviso para o próximo da fila", emails)
+	}
+
+	// Quem sai da fila devolve o horário ao próximo; sem ninguém esperando, ele volta a ficar livre
+	if err := f.waitlists.Leave(ctx, second.ID); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	if _, err := f.booking.BookAppointment(ctx, svc.ID, f.professional.ID, uuid.New(), slotAt(10, 0), nil); err != nil {
+		t.Fatalf("BookAppointment depois de esvaziada a fila: %v", err)
+	}
+}
@@ -0,0 +1,396 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config agrupa as configurações da aplicação carregadas do ambiente
+type Config struct {
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Login       LoginConfig
+	Email       EmailConfig
+	OIDC        OIDCConfig
+	Admin       AdminConfig
+	Booking     BookingConfig
+	Idempotency IdempotencyConfig
+}
+
+// JWTConfig configura a emissão e validação dos tokens de acesso
+type JWTConfig struct {
+	Algorithm      string
+	Secret         string
+	PrivateKeyPath string
+	PublicKeyPath  string
+	Issuer         string
+	AccessTTL      time.Duration
+	RefreshTTL     time.Duration
+}
+
+// AuthConfig configura a verificação de email, a redefinição de senha e o 2FA
+type AuthConfig struct {
+	RequireEmailVerification bool
+	EmailVerificationTTL     time.Duration
+	PasswordResetTTL         time.Duration
+	MFAChallengeTTL          time.Duration
+	MFAIssuer                string
+}
+
+// LoginConfig configura a proteção contra força bruta no login
+type LoginConfig struct {
+	MaxFailuresPerEmail int
+	MaxFailuresPerIP    int
+	FreeAttempts        int
+	BaseDelay           time.Duration
+	MaxDelay            time.Duration
+	LockoutDuration     time.Duration
+}
+
+// EmailConfig configura o envio de emails e os links enviados neles
+type EmailConfig struct {
+	Sender      string
+	FilePath    string
+	LinkBaseURL string
+}
+
+// OIDCConfig configura o login social com provedores OAuth2/OIDC
+type OIDCConfig struct {
+	Providers        []OIDCProviderConfig
+	RedirectBaseURL  string
+	StateTTL         time.Duration
+	FakeEnabled      bool
+	FakeAuthorizeURL string
+}
+
+// AdminConfig configura a conta inicial de administrador e a personificação de usuários
+type AdminConfig struct {
+	Name             string
+	Email            string
+	Password         string
+	ImpersonationTTL time.Duration
+}
+
+// BookingConfig configura a busca de horários livres, as reservas temporárias e a lista de espera
+type BookingConfig struct {
+	SlotGranularity time.Duration
+	BufferTime      time.Duration
+	MaxSearchRange  time.Duration
+	// HoldTTL é quanto tempo uma reserva temporária feita em POST /holds segura o horário
+	HoldTTL time.Duration
+	// WaitlistOfferTTL é o prazo para o cliente aceitar um horário oferecido pela lista de espera
+	WaitlistOfferTTL time.Duration
+	// HoldSweepInterval é o intervalo entre as verificações de reservas expiradas
+	HoldSweepInterval time.Duration
+	// MaxHoldsPerClient limita as reservas temporárias válidas ao mesmo tempo por cliente
+	MaxHoldsPerClient int
+	// HoldRateLimit é quantas reservas temporárias cada usuário ou chave de API pode pedir por HoldRateWindow
+	HoldRateLimit  int
+	HoldRateWindow time.Duration
+}
+
+// IdempotencyConfig configura a validade das chaves enviadas no header Idempotency-Key
+type IdempotencyConfig struct {
+	KeyTTL time.Duration
+	// LockTTL é por quanto tempo uma requisição em andamento detém a chave antes que uma repetição a assuma
+	LockTTL       time.Duration
+	SweepInterval time.Duration
+}
+
+// OIDCProviderConfig identifica a aplicação registrada em um provedor OIDC
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+func Load() (*Config, error) {
+	accessTTL, err := getDuration("JWT_EXPIRATION", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL, err := getDuration("JWT_REFRESH_EXPIRATION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationTTL, err := getDuration("EMAIL_VERIFICATION_EXPIRATION", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	resetTTL, err := getDuration("PASSWORD_RESET_EXPIRATION", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	mfaChallengeTTL, err := getDuration("MFA_CHALLENGE_EXPIRATION", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	requireVerification, err := getBool("REQUIRE_EMAIL_VERIFICATION", false)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := loadLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	oidc, err := loadOIDC()
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := loadAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := loadBooking()
+	if err != nil {
+		return nil, err
+	}
+
+	idempotency, err := loadIdempotency()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		JWT: JWTConfig{
+			Algorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+			Secret:         os.Getenv("JWT_SECRET"),
+			PrivateKeyPath: os.Getenv("JWT_PRIVATE_KEY_PATH"),
+			PublicKeyPath:  os.Getenv("JWT_PUBLIC_KEY_PATH"),
+			Issuer:         getEnv("JWT_ISSUER", "youmeet"),
+			AccessTTL:      accessTTL,
+			RefreshTTL:     refreshTTL,
+		},
+		Auth: AuthConfig{
+			RequireEmailVerification: requireVerification,
+			EmailVerificationTTL:     verificationTTL,
+			PasswordResetTTL:         resetTTL,
+			MFAChallengeTTL:          mfaChallengeTTL,
+			MFAIssuer:                getEnv("MFA_ISSUER", "YouMeet"),
+		},
+		Login: *login,
+		Email: EmailConfig{
+			Sender:      getEnv("EMAIL_SENDER", "log"),
+			FilePath:    getEnv("EMAIL_FILE_PATH", "emails.log"),
+			LinkBaseURL: getEnv("APP_BASE_URL", "http://localhost:3000"),
+		},
+		OIDC:        *oidc,
+		Admin:       *admin,
+		Booking:     *booking,
+		Idempotency: *idempotency,
+	}, nil
+}
+
+func loadLogin() (*LoginConfig, error) {
+	var err error
+	login := &LoginConfig{}
+
+	if login.MaxFailuresPerEmail, err = getInt("LOGIN_MAX_FAILURES_PER_EMAIL", 5); err != nil {
+		return nil, err
+	}
+	if login.MaxFailuresPerIP, err = getInt("LOGIN_MAX_FAILURES_PER_IP", 20); err != nil {
+		return nil, err
+	}
+	if login.FreeAttempts, err = getInt("LOGIN_FREE_ATTEMPTS", 2); err != nil {
+		return nil, err
+	}
+	if login.BaseDelay, err = getDuration("LOGIN_BASE_DELAY", time.Second); err != nil {
+		return nil, err
+	}
+	if login.MaxDelay, err = getDuration("LOGIN_MAX_DELAY", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if login.LockoutDuration, err = getDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute); err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+// loadAdmin lê a conta de administrador criada na inicialização; sem ADMIN_EMAIL nenhuma conta é criada
+func loadAdmin() (*AdminConfig, error) {
+	var err error
+	admin := &AdminConfig{
+		Name:     getEnv("ADMIN_NAME", "Administrador"),
+		Email:    os.Getenv("ADMIN_EMAIL"),
+		Password: os.Getenv("ADMIN_PASSWORD"),
+	}
+
+	if admin.Email != "" && len(admin.Password) < 12 {
+		return nil, fmt.Errorf("ADMIN_PASSWORD deve ter pelo menos 12 caracteres")
+	}
+
+	if admin.ImpersonationTTL, err = getDuration("ADMIN_IMPERSONATION_EXPIRATION", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	// Tokens de personificação não têm refresh e precisam expirar rápido
+	if admin.ImpersonationTTL <= 0 || admin.ImpersonationTTL > time.Hour {
+		return nil, fmt.Errorf("ADMIN_IMPERSONATION_EXPIRATION deve estar entre 0 e 1h")
+	}
+
+	return admin, nil
+}
+
+func loadBooking() (*BookingConfig, error) {
+	var err error
+	booking := &BookingConfig{}
+
+	if booking.SlotGranularity, err = getDuration("SLOT_GRANULARITY", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if booking.BufferTime, err = getDuration("BOOKING_BUFFER", 0); err != nil {
+		return nil, err
+	}
+	if booking.MaxSearchRange, err = getDuration("SLOT_SEARCH_MAX_RANGE", 62*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if booking.HoldTTL, err = getDuration("HOLD_EXPIRATION", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if booking.WaitlistOfferTTL, err = getDuration("WAITLIST_OFFER_EXPIRATION", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if booking.HoldSweepInterval, err = getDuration("HOLD_SWEEP_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if booking.MaxHoldsPerClient, err = getInt("HOLD_MAX_PER_CLIENT", 3); err != nil {
+		return nil, err
+	}
+	if booking.HoldRateLimit, err = getInt("HOLD_RATE_LIMIT", 20); err != nil {
+		return nil, err
+	}
+	if booking.HoldRateWindow, err = getDuration("HOLD_RATE_WINDOW", time.Minute); err != nil {
+		return nil, err
+	}
+	if booking.SlotGranularity < time.Minute {
+		return nil, fmt.Errorf("SLOT_GRANULARITY deve ser de pelo menos 1m")
+	}
+	if booking.BufferTime < 0 {
+		return nil, fmt.Errorf("BOOKING_BUFFER não pode ser negativo")
+	}
+	if booking.HoldTTL < time.Minute || booking.HoldTTL > time.Hour {
+		return nil, fmt.Errorf("HOLD_EXPIRATION deve estar entre 1m e 1h")
+	}
+	if booking.WaitlistOfferTTL < time.Minute {
+		return nil, fmt.Errorf("WAITLIST_OFFER_EXPIRATION deve ser de pelo menos 1m")
+	}
+	if booking.HoldSweepInterval < time.Second {
+		return nil, fmt.Errorf("HOLD_SWEEP_INTERVAL deve ser de pelo menos 1s")
+	}
+	if booking.MaxHoldsPerClient < 1 {
+		return nil, fmt.Errorf("HOLD_MAX_PER_CLIENT deve ser de pelo menos 1")
+	}
+	if booking.HoldRateLimit < 1 {
+		return nil, fmt.Errorf("HOLD_RATE_LIMIT deve ser de pelo menos 1")
+	}
+	if booking.HoldRateWindow < time.Second {
+		return nil, fmt.Errorf("HOLD_RATE_WINDOW deve ser de pelo menos 1s")
+	}
+
+	return booking, nil
+}
+
+func loadIdempotency() (*IdempotencyConfig, error) {
+	var err error
+	idempotency := &IdempotencyConfig{}
+
+	if idempotency.KeyTTL, err = getDuration("IDEMPOTENCY_KEY_EXPIRATION", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if idempotency.LockTTL, err = getDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute); err != nil {
+		return nil, err
+	}
+	if idempotency.SweepInterval, err = getDuration("IDEMPOTENCY_SWEEP_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if idempotency.KeyTTL < time.Minute {
+		return nil, fmt.Errorf("IDEMPOTENCY_KEY_EXPIRATION deve ser de pelo menos 1m")
+	}
+	if idempotency.LockTTL < time.Second || idempotency.LockTTL > idempotency.KeyTTL {
+		return nil, fmt.Errorf("IDEMPOTENCY_LOCK_TIMEOUT deve estar entre 1s e IDEMPOTENCY_KEY_EXPIRATION")
+	}
+	if idempotency.SweepInterval < time.Second {
+		return nil, fmt.Errorf("IDEMPOTENCY_SWEEP_INTERVAL deve ser de pelo menos 1s")
+	}
+
+	return idempotency, nil
+}
+
+// loadOIDC lê OIDC_PROVIDERS (ex.: "google,microsoft") e as variáveis OIDC_<NOME>_* de cada provedor
+func loadOIDC() (*OIDCConfig, error) {
+	var err error
+	oidc := &OIDCConfig{
+		RedirectBaseURL:  strings.TrimRight(getEnv("OIDC_REDIRECT_BASE_URL", "http://localhost:8080/auth/oidc"), "/"),
+		FakeAuthorizeURL: getEnv("OIDC_FAKE_AUTHORIZE_URL", "http://localhost:8080/oidc/fake/authorize"),
+	}
+
+	if oidc.StateTTL, err = getDuration("OIDC_STATE_EXPIRATION", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if oidc.FakeEnabled, err = getBool("OIDC_FAKE_ENABLED", false); err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER_URL"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.IssuerURL == "" || provider.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER_URL e %sCLIENT_ID são obrigatórios", prefix, prefix)
+		}
+		oidc.Providers = append(oidc.Providers, provider)
+	}
+
+	return oidc, nil
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -1,8 +1,11 @@
 package database
 
 import (
+	"errors"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"youmeet/internal/adapters/repositories"
 )
 
@@ -11,7 +14,8 @@ type PostgresClient struct {
 }
 
 func NewPostgresClient(dsn string) (repositories.DBClient, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError converte violações de índice único em gorm.ErrDuplicatedKey
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
@@ -20,11 +24,23 @@ func NewPostgresClient(dsn string) (repositories.DBClient, error) {
 }
 
 func (p *PostgresClient) Create(value interface{}) error {
-	return p.db.Create(value).Error
+	err := p.db.Create(value).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repositories.ErrDuplicateKey
+	}
+	return err
+}
+
+func (p *PostgresClient) Save(value interface{}) error {
+	return p.db.Save(value).Error
 }
 
 func (p *PostgresClient) First(dest interface{}, conds ...interface{}) error {
-	return p.db.First(dest, conds...).Error
+	err := p.db.First(dest, conds...).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repositories.ErrRecordNotFound
+	}
+	return err
 }
 
 func (p *PostgresClient) Find(dest interface{}, conds ...interface{}) error {
@@ -35,10 +51,42 @@ func (p *PostgresClient) Where(query interface{}, args ...interface{}) repositor
 	return &PostgresClient{db: p.db.Where(query, args...)}
 }
 
+func (p *PostgresClient) Order(value interface{}) repositories.DBClient {
+	return &PostgresClient{db: p.db.Order(value)}
+}
+
+func (p *PostgresClient) Limit(limit int) repositories.DBClient {
+	return &PostgresClient{db: p.db.Limit(limit)}
+}
+
+func (p *PostgresClient) Preload(query string, args ...interface{}) repositories.DBClient {
+	return &PostgresClient{db: p.db.Preload(query, args...)}
+}
+
+func (p *PostgresClient) ForUpdate() repositories.DBClient {
+	return &PostgresClient{db: p.db.Clauses(clause.Locking{Strength: "UPDATE"})}
+}
+
+func (p *PostgresClient) IgnoreConflicts() repositories.DBClient {
+	return &PostgresClient{db: p.db.Clauses(clause.OnConflict{DoNothing: true})}
+}
+
 func (p *PostgresClient) Delete(value interface{}, conds ...interface{}) error {
 	return p.db.Delete(value, conds...).Error
 }
 
+// Updates atualiza as colunas informadas nos registros filtrados e retorna quantos foram afetados
+func (p *PostgresClient) Updates(model interface{}, values interface{}) (int64, error) {
+	result := p.db.Model(model).Updates(values)
+	return result.RowsAffected, result.Error
+}
+
 func (p *PostgresClient) AutoMigrate(dst ...interface{}) error {
 	return p.db.AutoMigrate(dst...)
-}
\ No newline at end of file
+}
+
+func (p *PostgresClient) Transaction(fn func(tx repositories.DBClient) error) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&PostgresClient{db: tx})
+	})
+}
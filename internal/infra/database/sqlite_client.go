@@ -1,8 +1,11 @@
 package database
 
 import (
+	"errors"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"youmeet/internal/adapters/repositories"
 )
 
@@ -11,20 +14,41 @@ type SQLiteClient struct {
 }
 
 func NewSQLiteClient(dbPath string) (repositories.DBClient, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	// TranslateError converte violações de índice único em gorm.ErrDuplicatedKey
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
-	
+
+	// SQLite não tem bloqueio por linha: com uma única conexão as transações são
+	// serializadas e ForUpdate pode ser ignorado sem permitir leituras concorrentes
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
 	return &SQLiteClient{db: db}, nil
 }
 
 func (s *SQLiteClient) Create(value interface{}) error {
-	return s.db.Create(value).Error
+	err := s.db.Create(value).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repositories.ErrDuplicateKey
+	}
+	return err
+}
+
+func (s *SQLiteClient) Save(value interface{}) error {
+	return s.db.Save(value).Error
 }
 
 func (s *SQLiteClient) First(dest interface{}, conds ...interface{}) error {
-	return s.db.First(dest, conds...).Error
+	err := s.db.First(dest, conds...).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repositories.ErrRecordNotFound
+	}
+	return err
 }
 
 func (s *SQLiteClient) Find(dest interface{}, conds ...interface{}) error {
@@ -35,10 +59,42 @@ func (s *SQLiteClient) Where(query interface{}, args ...interface{}) repositorie
 	return &SQLiteClient{db: s.db.Where(query, args...)}
 }
 
+func (s *SQLiteClient) Order(value interface{}) repositories.DBClient {
+	return &SQLiteClient{db: s.db.Order(value)}
+}
+
+func (s *SQLiteClient) Limit(limit int) repositories.DBClient {
+	return &SQLiteClient{db: s.db.Limit(limit)}
+}
+
+func (s *SQLiteClient) Preload(query string, args ...interface{}) repositories.DBClient {
+	return &SQLiteClient{db: s.db.Preload(query, args...)}
+}
+
+func (s *SQLiteClient) ForUpdate() repositories.DBClient {
+	return &SQLiteClient{db: s.db.Clauses(clause.Locking{Strength: "UPDATE"})}
+}
+
+func (s *SQLiteClient) IgnoreConflicts() repositories.DBClient {
+	return &SQLiteClient{db: s.db.Clauses(clause.OnConflict{DoNothing: true})}
+}
+
 func (s *SQLiteClient) Delete(value interface{}, conds ...interface{}) error {
 	return s.db.Delete(value, conds...).Error
 }
 
+// Updates atualiza as colunas informadas nos registros filtrados e retorna quantos foram afetados
+func (s *SQLiteClient) Updates(model interface{}, values interface{}) (int64, error) {
+	result := s.db.Model(model).Updates(values)
+	return result.RowsAffected, result.Error
+}
+
 func (s *SQLiteClient) AutoMigrate(dst ...interface{}) error {
 	return s.db.AutoMigrate(dst...)
-}
\ No newline at end of file
+}
+
+func (s *SQLiteClient) Transaction(fn func(tx repositories.DBClient) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&SQLiteClient{db: tx})
+	})
+}
@@ -0,0 +1,19 @@
+package email
+
+import (
+	"fmt"
+
+	"youmeet/internal/core/domain/notification"
+	"youmeet/internal/infra/config"
+)
+
+func NewEmailSender(cfg config.EmailConfig) (notification.EmailSender, error) {
+	switch cfg.Sender {
+	case "file":
+		return NewFileSender(cfg.FilePath), nil
+	case "log", "":
+		return NewLogSender(), nil
+	default:
+		return nil, fmt.Errorf("EMAIL_SENDER não suportado: %s", cfg.Sender)
+	}
+}
@@ -0,0 +1,20 @@
+package email
+
+import (
+	"context"
+	"log"
+
+	"youmeet/internal/core/domain/notification"
+)
+
+// LogSender escreve os emails no log da aplicação, para desenvolvimento local
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(ctx context.Context, email *notification.Email) error {
+	log.Printf("email to=%s subject=%q\n%s", email.To, email.Subject, email.Body)
+	return nil
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every executa fn a cada interval até ctx ser cancelado. Falhas ficam registradas no log e a
+// execução segue no próximo ciclo.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("job %s: %v", name, err)
+			}
+		}
+	}
+}
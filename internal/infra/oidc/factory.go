@@ -0,0 +1,33 @@
+package oidc
+
+import (
+	"context"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/infra/config"
+)
+
+// NewProviders cria os provedores configurados. O provedor fake, quando habilitado,
+// também é retornado separadamente para que sua tela de autorização seja exposta.
+func NewProviders(ctx context.Context, cfg config.OIDCConfig) ([]auth.IdentityProvider, *FakeProvider, error) {
+	var providers []auth.IdentityProvider
+	for _, providerCfg := range cfg.Providers {
+		provider, err := NewProvider(ctx, providerCfg, callbackURL(cfg, providerCfg.Name))
+		if err != nil {
+			return nil, nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	var fake *FakeProvider
+	if cfg.FakeEnabled {
+		fake = NewFakeProvider(cfg.FakeAuthorizeURL, callbackURL(cfg, FakeProviderName))
+		providers = append(providers, fake)
+	}
+
+	return providers, fake, nil
+}
+
+func callbackURL(cfg config.OIDCConfig, provider string) string {
+	return cfg.RedirectBaseURL + "/" + provider + "/callback"
+}
@@ -0,0 +1,149 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+)
+
+// FakeProviderName é o nome sob o qual o provedor local é registrado
+const FakeProviderName = "fake"
+
+const fakeCodeTTL = time.Minute
+
+// FakeProvider é um provedor de identidade em memória para desenvolvimento e testes.
+// Ele implementa a tela de autorização e a troca de código (com verificação PKCE)
+// sem depender de um IdP real.
+type FakeProvider struct {
+	authorizeURL string
+	redirectURL  string
+
+	mu     sync.Mutex
+	grants map[string]fakeGrant
+}
+
+type fakeGrant struct {
+	identity      auth.ExternalIdentity
+	codeChallenge string
+	nonce         string
+	expiresAt     time.Time
+}
+
+func NewFakeProvider(authorizeURL, redirectURL string) *FakeProvider {
+	return &FakeProvider{
+		authorizeURL: authorizeURL,
+		redirectURL:  redirectURL,
+		grants:       make(map[string]fakeGrant),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *FakeProvider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	query := url.Values{
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	return p.authorizeURL + "?" + query.Encode()
+}
+
+func (p *FakeProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*auth.ExternalIdentity, error) {
+	p.mu.Lock()
+	grant, ok := p.grants[code]
+	delete(p.grants, code)
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(grant.expiresAt) {
+		return nil, errors.New("código de autorização inválido")
+	}
+	sum := sha256.Sum256([]byte(codeVerifier))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != grant.codeChallenge {
+		return nil, errors.New("code_verifier não confere com o code_challenge")
+	}
+	if grant.nonce != nonce {
+		return nil, errors.New("nonce não confere")
+	}
+
+	identity := grant.identity
+	return &identity, nil
+}
+
+var fakeLoginForm = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><body>
+<h1>Fake OIDC</h1>
+<form method="get">
+{{range $name, $values := .}}{{range $values}}<input type="hidden" name="{{$name}}" value="{{.}}">{{end}}{{end}}
+<label>Email <input name="email" type="email" required></label>
+<label>Nome <input name="name"></label>
+<button type="submit">Entrar</button>
+</form>
+</body></html>`))
+
+// Authorize simula a tela de login do provedor. Sem o parâmetro email, exibe um formulário;
+// com ele, emite o código e redireciona para o callback. Parâmetros opcionais:
+// name, sub (padrão: o email) e email_verified (padrão: true).
+func (p *FakeProvider) Authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("email") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fakeLoginForm.Execute(w, query)
+		return
+	}
+	if query.Get("state") == "" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "state e code_challenge S256 são obrigatórios", http.StatusBadRequest)
+		return
+	}
+
+	emailVerified := true
+	if raw := query.Get("email_verified"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			http.Error(w, "email_verified inválido", http.StatusBadRequest)
+			return
+		}
+		emailVerified = parsed
+	}
+	subject := query.Get("sub")
+	if subject == "" {
+		subject = query.Get("email")
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	code := base64.RawURLEncoding.EncodeToString(buf)
+
+	p.mu.Lock()
+	p.grants[code] = fakeGrant{
+		identity: auth.ExternalIdentity{
+			Provider:      FakeProviderName,
+			Subject:       subject,
+			Email:         query.Get("email"),
+			EmailVerified: emailVerified,
+			Name:          query.Get("name"),
+		},
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		expiresAt:     time.Now().Add(fakeCodeTTL),
+	}
+	p.mu.Unlock()
+
+	callback := url.Values{"code": {code}, "state": {query.Get("state")}}
+	http.Redirect(w, r, p.redirectURL+"?"+callback.Encode(), http.StatusFound)
+}
@@ -0,0 +1,139 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	testRedirectURL = "http://localhost:8080/auth/oidc/fake/callback"
+	testVerifier    = "verificador-de-teste-com-entropia-suficiente"
+)
+
+func testChallenge() string {
+	sum := sha256.Sum256([]byte(testVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authorize percorre a tela de login do provedor fake e retorna o redirecionamento para o callback
+func authorize(t *testing.T, p *FakeProvider, authURL string, extra url.Values) *url.URL {
+	t.Helper()
+
+	target, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("URL de autorização inválida: %v", err)
+	}
+	query := target.Query()
+	for name, values := range extra {
+		query[name] = values
+	}
+	target.RawQuery = query.Encode()
+
+	rec := httptest.NewRecorder()
+	p.Authorize(rec, httptest.NewRequest(http.MethodGet, target.String(), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("Authorize respondeu %d, esperado 302: %s", rec.Code, rec.Body.String())
+	}
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("redirecionamento inválido: %v", err)
+	}
+	return location
+}
+
+func TestFakeProviderCompleteFlow(t *testing.T) {
+	p := NewFakeProvider("http://localhost:8080/oidc/fake/authorize", testRedirectURL)
+
+	authURL := p.AuthCodeURL("state-1", "nonce-1", testChallenge())
+	callback := authorize(t, p, authURL, url.Values{"email": {"ana@example.com"}, "name": {"Ana"}})
+
+	if got := callback.Scheme + "://" + callback.Host + callback.Path; got != testRedirectURL {
+		t.Fatalf("callback = %s, esperado %s", got, testRedirectURL)
+	}
+	if callback.Query().Get("state") != "state-1" {
+		t.Fatalf("state não foi devolvido ao callback: %s", callback.RawQuery)
+	}
+
+	identity, err := p.Exchange(context.Background(), callback.Query().Get("code"), testVerifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Provider != FakeProviderName || identity.Subject != "ana@example.com" ||
+		identity.Email != "ana@example.com" || !identity.EmailVerified || identity.Name != "Ana" {
+		t.Fatalf("identidade inesperada: %+v", identity)
+	}
+}
+
+func TestFakeProviderRejectsInvalidExchanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+	}{
+		{name: "code_verifier diferente", verifier: "outro-verificador", nonce: "nonce-1"},
+		{name: "nonce diferente", verifier: testVerifier, nonce: "outro-nonce"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewFakeProvider("http://localhost:8080/oidc/fake/authorize", testRedirectURL)
+			callback := authorize(t, p, p.AuthCodeURL("state-1", "nonce-1", testChallenge()), url.Values{"email": {"ana@example.com"}})
+
+			if _, err := p.Exchange(context.Background(), callback.Query().Get("code"), tt.verifier, tt.nonce); err == nil {
+				t.Fatal("Exchange aceitou uma troca inválida")
+			}
+		})
+	}
+}
+
+func TestFakeProviderCodeIsSingleUse(t *testing.T) {
+	p := NewFakeProvider("http://localhost:8080/oidc/fake/authorize", testRedirectURL)
+	callback := authorize(t, p, p.AuthCodeURL("state-1", "nonce-1", testChallenge()), url.Values{"email": {"ana@example.com"}})
+	code := callback.Query().Get("code")
+
+	if _, err := p.Exchange(context.Background(), code, testVerifier, "nonce-1"); err != nil {
+		t.Fatalf("primeira troca: %v", err)
+	}
+	if _, err := p.Exchange(context.Background(), code, testVerifier, "nonce-1"); err == nil {
+		t.Fatal("o mesmo código foi aceito duas vezes")
+	}
+}
+
+func TestFakeProviderAuthorize(t *testing.T) {
+	p := NewFakeProvider("http://localhost:8080/oidc/fake/authorize", testRedirectURL)
+
+	t.Run("sem email exibe o formulário", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		p.Authorize(rec, httptest.NewRequest(http.MethodGet, p.AuthCodeURL("state-1", "nonce-1", testChallenge()), nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<form") {
+			t.Fatalf("esperado o formulário de login, recebido %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("exige PKCE S256", func(t *testing.T) {
+		query := url.Values{"state": {"state-1"}, "email": {"ana@example.com"}, "code_challenge": {testChallenge()}, "code_challenge_method": {"plain"}}
+		rec := httptest.NewRecorder()
+		p.Authorize(rec, httptest.NewRequest(http.MethodGet, "/oidc/fake/authorize?"+query.Encode(), nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("esperado 400, recebido %d", rec.Code)
+		}
+	})
+
+	t.Run("repassa email_verified", func(t *testing.T) {
+		callback := authorize(t, p, p.AuthCodeURL("state-1", "nonce-1", testChallenge()), url.Values{"email": {"ana@example.com"}, "email_verified": {"false"}, "sub": {"ana-123"}})
+		identity, err := p.Exchange(context.Background(), callback.Query().Get("code"), testVerifier, "nonce-1")
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if identity.EmailVerified || identity.Subject != "ana-123" {
+			t.Fatalf("identidade inesperada: %+v", identity)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/infra/config"
+)
+
+// Provider implementa auth.IdentityProvider para qualquer provedor OpenID Connect
+type Provider struct {
+	name     string
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProvider executa a descoberta (.well-known/openid-configuration) do emissor configurado
+func NewProvider(ctx context.Context, cfg config.OIDCProviderConfig, redirectURL string) (*Provider, error) {
+	discovered, err := gooidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("falha na descoberta do provedor %s: %w", cfg.Name, err)
+	}
+
+	return &Provider{
+		name: cfg.Name,
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     discovered.Endpoint(),
+			RedirectURL:  redirectURL,
+			Scopes:       cfg.Scopes,
+		},
+		verifier: discovered.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	return p.oauth.AuthCodeURL(state,
+		gooidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*auth.ExternalIdentity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("resposta do provedor sem id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("nonce do id_token não confere")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &auth.ExternalIdentity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package token
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/infra/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type jwtClaims struct {
+	Role    string    `json:"role"`
+	Purpose string    `json:"purpose"`
+	Actor   *jwtActor `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// jwtActor segue o claim "act" da RFC 8693: quem age em nome do subject
+type jwtActor struct {
+	Subject string `json:"sub"`
+}
+
+type JWTManager struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+}
+
+func NewJWTManager(cfg config.JWTConfig) (auth.TokenManager, error) {
+	m := &JWTManager{issuer: cfg.Issuer}
+
+	switch cfg.Algorithm {
+	case "HS256":
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("JWT_SECRET deve ter pelo menos 32 caracteres")
+		}
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(cfg.Secret)
+		m.verifyKey = []byte(cfg.Secret)
+
+	case "RS256":
+		privateKey, err := loadPrivateKey(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &privateKey.PublicKey
+		if cfg.PublicKeyPath != "" {
+			publicKey, err = loadPublicKey(cfg.PublicKeyPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		m.method = jwt.SigningMethodRS256
+		m.signKey = privateKey
+		m.verifyKey = publicKey
+
+	default:
+		return nil, fmt.Errorf("algoritmo JWT não suportado: %s", cfg.Algorithm)
+	}
+
+	return m, nil
+}
+
+func (m *JWTManager) Generate(claims *auth.Claims) (string, error) {
+	now := time.Now()
+	var actor *jwtActor
+	if claims.ImpersonatorID != nil {
+		actor = &jwtActor{Subject: claims.ImpersonatorID.String()}
+	}
+
+	token := jwt.NewWithClaims(m.method, jwtClaims{
+		Role:    claims.Role,
+		Purpose: claims.Purpose,
+		Actor:   actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   claims.UserID.String(),
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+	})
+	return token.SignedString(m.signKey)
+}
+
+func (m *JWTManager) Parse(tokenString string) (*auth.Claims, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+
+	result := &auth.Claims{
+		UserID:    userID,
+		Role:      claims.Role,
+		Purpose:   claims.Purpose,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.Actor != nil {
+		impersonatorID, err := uuid.Parse(claims.Actor.Subject)
+		if err != nil {
+			return nil, auth.ErrInvalidToken
+		}
+		result.ImpersonatorID = &impersonatorID
+	}
+
+	return result, nil
+}
+
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_PATH é obrigatório para RS256")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
+
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(data)
+}
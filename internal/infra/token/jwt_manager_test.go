@@ -0,0 +1,145 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"youmeet/internal/core/domain/auth"
+	"youmeet/internal/infra/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testSecret = "segredo-de-teste-com-mais-de-32-caracteres"
+
+func newHS256(t *testing.T, secret, issuer string) auth.TokenManager {
+	t.Helper()
+	m, err := NewJWTManager(config.JWTConfig{Algorithm: "HS256", Secret: secret, Issuer: issuer})
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+	return m
+}
+
+func TestJWTManagerRoundTrip(t *testing.T) {
+	m := newHS256(t, testSecret, "youmeet")
+	claims := &auth.Claims{UserID: uuid.New(), Role: "client", ExpiresAt: time.Now().Add(time.Minute).Truncate(time.Second)}
+
+	signed, err := m.Generate(claims)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	parsed, err := m.Parse(signed)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if parsed.UserID != claims.UserID || parsed.Role != claims.Role || !parsed.ExpiresAt.Equal(claims.ExpiresAt) {
+		t.Fatalf("claims = %+v, esperado %+v", parsed, claims)
+	}
+}
+
+func TestJWTManagerRejectsInvalidTokens(t *testing.T) {
+	m := newHS256(t, testSecret, "youmeet")
+	valid := &auth.Claims{UserID: uuid.New(), Role: "client", ExpiresAt: time.Now().Add(time.Minute)}
+
+	expired, err := m.Generate(&auth.Claims{UserID: uuid.New(), Role: "client", ExpiresAt: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	otherSecret, err := newHS256(t, "outro-segredo-de-teste-com-mais-de-32", "youmeet").Generate(valid)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	otherIssuer, err := newHS256(t, testSecret, "outro-emissor").Generate(valid)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "youmeet",
+		Subject:   valid.UserID.String(),
+		ExpiresAt: jwt.NewNumericDate(valid.ExpiresAt),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	noExpiration, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:  "youmeet",
+		Subject: valid.UserID.String(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	tests := map[string]string{
+		"expirado":       expired,
+		"outro segredo":  otherSecret,
+		"outro emissor":  otherIssuer,
+		"sem assinatura": unsigned,
+		"sem expiração":  noExpiration,
+		"malformado":     "nao.e.jwt",
+	}
+	for name, signed := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := m.Parse(signed); !errors.Is(err, auth.ErrInvalidToken) {
+				t.Fatalf("erro = %v, esperado %v", err, auth.ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestJWTManagerRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "private.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, pemData, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	m, err := NewJWTManager(config.JWTConfig{Algorithm: "RS256", PrivateKeyPath: path, Issuer: "youmeet"})
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+	signed, err := m.Generate(&auth.Claims{UserID: uuid.New(), Role: "company", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, err := m.Parse(signed); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	// Um token HS256 assinado com a chave pública não pode passar por RS256
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    "youmeet",
+		Subject:   uuid.New().String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	if _, err := m.Parse(forged); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("erro = %v, esperado %v", err, auth.ErrInvalidToken)
+	}
+}
+
+func TestNewJWTManagerRejectsWeakConfiguration(t *testing.T) {
+	if _, err := NewJWTManager(config.JWTConfig{Algorithm: "HS256", Secret: "curto"}); err == nil {
+		t.Fatal("segredo curto foi aceito")
+	}
+	if _, err := NewJWTManager(config.JWTConfig{Algorithm: "none", Secret: testSecret}); err == nil {
+		t.Fatal("algoritmo desconhecido foi aceito")
+	}
+	if _, err := NewJWTManager(config.JWTConfig{Algorithm: "RS256"}); err == nil {
+		t.Fatal("RS256 sem chave privada foi aceito")
+	}
+}